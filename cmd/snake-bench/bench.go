@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"snake-game/internal/ai"
+	"snake-game/internal/core"
+	"snake-game/internal/sim"
+	"strconv"
+	"text/tabwriter"
+)
+
+type benchmark struct {
+	rules    sim.Rules
+	mode     sim.GameMode
+	maxTicks int
+}
+
+// result — итоги всех игр одного контроллера на одном уровне.
+type result struct {
+	// level — имя файла уровня без расширения
+	level      string
+	controller string
+	scores     []int
+	ticks      []int
+	// deaths — сколько игр закончилось каждой причиной; NotDead — змея дожила до лимита тиков
+	deaths map[sim.DeathCause]int
+}
+
+func (b benchmark) run(name string, level *core.Level, controller ai.Controller, firstSeed uint64, games int) (result, error) {
+	res := result{
+		level:      name,
+		controller: controller.Name(),
+		deaths:     make(map[sim.DeathCause]int),
+	}
+
+	for seed := firstSeed; seed < firstSeed+uint64(games); seed++ {
+		world, err := sim.NewWorld(level, b.rules, b.mode, 1, seed)
+		if err != nil {
+			return res, err
+		}
+		// без еды на поле (змея заняла всё поле) классическая игра бесконечна
+		for !world.IsOver() && world.Tick < b.maxTicks && (world.Food != nil || !b.mode.SpawnsFood()) {
+			world.Step(ai.Input(controller, world, 0))
+		}
+
+		res.scores = append(res.scores, world.Scores[0])
+		res.ticks = append(res.ticks, world.Tick)
+		res.deaths[world.Causes[0]]++
+	}
+	return res, nil
+}
+
+func (r result) games() int {
+	return len(r.scores)
+}
+
+func average(values []int) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0
+	for _, v := range values {
+		sum += v
+	}
+	return float64(sum) / float64(len(values))
+}
+
+func median(values []int) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return float64(sorted[middle-1]+sorted[middle]) / 2
+	}
+	return float64(sorted[middle])
+}
+
+func seconds(ticks float64) float64 {
+	return ticks / sim.TicksPerSecond
+}
+
+var reportHeader = []string{
+	"level", "controller", "games",
+	"avg_score", "median_score", "avg_survival_s", "median_survival_s",
+	"wall", "border", "self", "alive",
+}
+
+func (r result) row() []string {
+	return []string{
+		r.level,
+		r.controller,
+		strconv.Itoa(r.games()),
+		fmt.Sprintf("%.2f", average(r.scores)),
+		fmt.Sprintf("%.1f", median(r.scores)),
+		fmt.Sprintf("%.1f", seconds(average(r.ticks))),
+		fmt.Sprintf("%.1f", seconds(median(r.ticks))),
+		strconv.Itoa(r.deaths[sim.WallCollision]),
+		strconv.Itoa(r.deaths[sim.BorderCollision]),
+		strconv.Itoa(r.deaths[sim.SelfCollision]),
+		strconv.Itoa(r.deaths[sim.NotDead]),
+	}
+}
+
+func writeCSV(w io.Writer, results []result) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(reportHeader); err != nil {
+		return err
+	}
+	for _, r := range results {
+		if err := writer.Write(r.row()); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeTable(w io.Writer, results []result) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	line := func(cells []string) {
+		for _, cell := range cells {
+			fmt.Fprint(writer, cell, "\t")
+		}
+		fmt.Fprintln(writer)
+	}
+
+	line(reportHeader)
+	for _, r := range results {
+		line(r.row())
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"snake-game/internal/ai"
+	"snake-game/internal/config"
+	"snake-game/internal/levels"
+	"snake-game/internal/sim"
+	"strings"
+)
+
+func main() {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	levelsDir := flag.String("levels", "levels", "directory with level files")
+	seeds := flag.Int("seeds", 100, "number of games per level and controller")
+	firstSeed := flag.Uint64("seed", 1, "first seed; games use seeds seed, seed+1, ...")
+	controllerNames := flag.String("controllers", strings.Join(controllerList(), ","), "comma-separated controllers to run")
+	modeName := flag.String("mode", sim.ClassicModeName, "game mode")
+	maxTicks := flag.Int("max-ticks", 10*60*sim.TicksPerSecond, "stop a game after this many ticks")
+	format := flag.String("format", "table", "output format: table or csv")
+	configPath := flag.String("config", "", "config file with game rules; default rules are used when empty")
+	flag.Parse()
+
+	if *format != "table" && *format != "csv" {
+		fmt.Fprintf(os.Stderr, "unknown format %q: expected table or csv\n", *format)
+		os.Exit(2)
+	}
+	if *seeds < 1 {
+		fmt.Fprintf(os.Stderr, "invalid number of seeds: expected positive value, received %d\n", *seeds)
+		os.Exit(2)
+	}
+
+	mode, err := sim.ModeByName(*modeName)
+	if err != nil {
+		logger.Error("invalid game mode", "error", err)
+		os.Exit(2)
+	}
+
+	names := strings.Split(*controllerNames, ",")
+	for _, name := range names {
+		if _, err := ai.ControllerByName(strings.TrimSpace(name)); err != nil {
+			logger.Error("invalid controller", "error", err)
+			os.Exit(2)
+		}
+	}
+
+	entries, err := loadLevels(*levelsDir, logger)
+	if err != nil {
+		logger.Error("failed to load levels", "dir", *levelsDir, "error", err)
+		os.Exit(1)
+	}
+	if len(entries) == 0 {
+		logger.Error("no levels were found", "dir", *levelsDir)
+		os.Exit(1)
+	}
+
+	// без -config правила фиксированные: результаты не зависят от настроек игрока и SNAKE_CONFIG
+	cfg := &config.Config{Settings: config.DefaultSettings()}
+	if *configPath != "" {
+		// LoadConfig молча берёт значения по умолчанию, если файла нет; опечатка в пути не должна так теряться
+		if _, err := os.Stat(*configPath); err != nil {
+			logger.Error("failed to load config", "path", *configPath, "error", err)
+			os.Exit(1)
+		}
+		cfg, err = config.LoadConfig(*configPath)
+		if err != nil {
+			logger.Error("failed to load config", "path", *configPath, "error", err)
+			os.Exit(1)
+		}
+		cfg.SetLogger(logger)
+	}
+	bench := benchmark{
+		rules:    sim.RulesFromConfig(cfg),
+		mode:     mode,
+		maxTicks: *maxTicks,
+	}
+
+	results := make([]result, 0, len(entries)*len(names))
+	for _, entry := range entries {
+		for _, name := range names {
+			// у каждого прогона свой экземпляр: контроллеры могут хранить состояние
+			controller, _ := ai.ControllerByName(strings.TrimSpace(name))
+			res, err := bench.run(entry.Name(), entry.Level, controller, *firstSeed, *seeds)
+			if err != nil {
+				logger.Error("failed to run level", "level", entry.Name(), "controller", controller.Name(), "error", err)
+				os.Exit(1)
+			}
+			results = append(results, res)
+		}
+	}
+
+	if *format == "csv" {
+		err = writeCSV(os.Stdout, results)
+	} else {
+		err = writeTable(os.Stdout, results)
+	}
+	if err != nil {
+		logger.Error("failed to write report", "error", err)
+		os.Exit(1)
+	}
+}
+
+func controllerList() []string {
+	names := make([]string, 0)
+	for _, controller := range ai.Controllers() {
+		names = append(names, controller.Name())
+	}
+	return names
+}
+
+// loadLevels возвращает уровни из dir, пропуская те, что не загрузились или не прошли проверку.
+func loadLevels(dir string, logger *slog.Logger) ([]levels.Entry, error) {
+	entries, err := levels.Scan(dir)
+	if err != nil {
+		return nil, err
+	}
+	valid := make([]levels.Entry, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsValid() {
+			logger.Warn("skipping level", "path", entry.Path, "error", entry.Err)
+			continue
+		}
+		valid = append(valid, entry)
+	}
+	return valid, nil
+}
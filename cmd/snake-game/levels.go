@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"snake-game/internal/levels"
+	"text/tabwriter"
+)
+
+const levelsUsage = "usage: snake-game levels list [--dir DIR] | validate [--dir DIR] [FILE...] | render FILE"
+
+// runLevels выполняет подкоманду levels и возвращает код завершения.
+func runLevels(opts options, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, levelsUsage)
+		return 2
+	}
+
+	fs := newFlagSet("levels "+args[0], &opts)
+	dir := fs.String("dir", levels.Dir, "directory with level files")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	a, err := newApp(opts, slog.LevelWarn)
+	if err != nil {
+		return 1
+	}
+
+	switch args[0] {
+	case "list":
+		return listLevels(a, *dir)
+	case "validate":
+		return validateLevels(a, *dir, fs.Args())
+	case "render":
+		if fs.NArg() != 1 {
+			fmt.Fprintln(os.Stderr, levelsUsage)
+			return 2
+		}
+		return renderLevel(a, fs.Arg(0))
+	default:
+		fmt.Fprintln(os.Stderr, levelsUsage)
+		return 2
+	}
+}
+
+func listLevels(a *app, dir string) int {
+	entries, err := levels.Scan(dir)
+	if err != nil {
+		a.logger.Error("failed to scan for levels", "dir", dir, "error", err)
+		return 1
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "FILE\tNAME\tSIZE\tDIFFICULTY\tAUTHOR\tSTATUS")
+	for _, entry := range entries {
+		name, size, difficulty, author := "-", "-", "-", "-"
+		if level := entry.Level; level != nil {
+			name = level.Name
+			size = fmt.Sprintf("%dx%d", level.GridWidth, level.GridHeight)
+			difficulty = valueOrDash(level.Difficulty)
+			author = valueOrDash(level.Author)
+		}
+		status := "ok"
+		switch {
+		case !entry.IsValid():
+			status = "invalid: " + levels.Reason(entry.Err)
+		case entry.VersusErr != nil:
+			status = "solo only: " + levels.Reason(entry.VersusErr)
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\n", entry.Name(), name, size, difficulty, author, status)
+	}
+	if err := writer.Flush(); err != nil {
+		a.logger.Error("failed to write level list", "error", err)
+		return 1
+	}
+	return 0
+}
+
+// validateLevels проверяет файлы paths или, если их нет, все уровни каталога dir.
+func validateLevels(a *app, dir string, paths []string) int {
+	entries := make([]levels.Entry, 0, len(paths))
+	if len(paths) == 0 {
+		scanned, err := levels.Scan(dir)
+		if err != nil {
+			a.logger.Error("failed to scan for levels", "dir", dir, "error", err)
+			return 1
+		}
+		entries = scanned
+	}
+	for _, path := range paths {
+		entries = append(entries, levels.NewEntry(path))
+	}
+
+	failed := 0
+	for _, entry := range entries {
+		if entry.IsValid() {
+			if entry.VersusErr != nil {
+				// в уровень можно играть одному, поэтому проверку он проходит
+				fmt.Printf("ok   %s (solo only: %s)\n", entry.Path, levels.Reason(entry.VersusErr))
+			} else {
+				fmt.Printf("ok   %s\n", entry.Path)
+			}
+			continue
+		}
+		failed++
+		fmt.Printf("FAIL %s: %s\n", entry.Path, levels.Reason(entry.Err))
+	}
+	fmt.Printf("%d level(s) checked, %d invalid\n", len(entries), failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func renderLevel(a *app, path string) int {
+	level, err := levels.Load(path)
+	if err != nil {
+		a.logger.Error("failed to load level", "path", path, "error", err)
+		return 1
+	}
+
+	fmt.Printf("%s %dx%d\n", level.Name, level.GridWidth, level.GridHeight)
+	fmt.Print(levels.Render(level))
+	fmt.Printf("%c wall, %c food, %c food zone, 1-9 player spawns\n", levels.RenderWall, levels.RenderFood, levels.RenderZone)
+	if err := levels.Validate(level); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: level is not playable: %s\n", levels.Reason(err))
+	}
+	return 0
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
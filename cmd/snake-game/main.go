@@ -1,10 +1,13 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"github.com/joho/godotenv"
 	"log/slog"
 	"os"
 	"snake-game/internal/assets"
+	"snake-game/internal/audio"
 	"snake-game/internal/config"
 	"snake-game/internal/game"
 	"snake-game/internal/storage"
@@ -13,10 +16,108 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+const usage = `usage: snake-game [options] [command]
+
+commands:
+  (none)                         start the game with the main menu
+  play --level FILE [--seed N] [--mode M]
+                                 start a game on the level right away
+  levels list [--dir DIR]        list levels and whether they are playable
+  levels validate [--dir DIR] [FILE...]
+                                 check levels, exit with 1 if any is invalid
+  levels render FILE             print the level as text
+  scores top [--level X] [--player Y] [--mode M] [--limit N] [--format table|json|csv]
+                                 print best scores
+  replay [--check] FILE          watch a replay; --check re-simulates it without a window
+  skins list                     list built-in and user skins and whether they are complete
+  migrate up | down [N] | status manage the records database schema
+
+options (also accepted after the command):
+  --config FILE     config file (default %s)
+  --log-level L     debug, info, warn or error
+  --db URL          records database URL, overrides DATABASE_URL
+  --tile-size N, --skin S, ...
+                    override a setting for this run (before the command or after play)
+`
+
+// options — общие флаги, которые понимает каждая команда.
+type options struct {
+	configPath string
+	logLevel   string
+	db         string
+}
+
+func (o *options) bind(fs *flag.FlagSet) {
+	fs.StringVar(&o.configPath, "config", o.configPath, "path to config file")
+	fs.StringVar(&o.logLevel, "log-level", o.logLevel, "log level: debug, info, warn or error")
+	fs.StringVar(&o.db, "db", o.db, "records database URL, overrides DATABASE_URL")
+}
+
+// app — то, что нужно каждой команде: загруженная конфигурация и логгер.
+type app struct {
+	cfg    *config.Config
+	logger *slog.Logger
+}
+
 func main() {
+	var opts options
+	flag.Usage = printUsage
+	opts.bind(flag.CommandLine)
+	config.RegisterFlags(flag.CommandLine)
+	flag.Parse()
 
-	opts := &slog.HandlerOptions{
-		Level: slog.LevelDebug,
+	args := flag.Args()
+	if len(args) == 0 {
+		os.Exit(runGame(opts, nil, nil))
+	}
+
+	switch args[0] {
+	case "play":
+		os.Exit(runPlay(opts, args[1:]))
+	case "levels":
+		os.Exit(runLevels(opts, args[1:]))
+	case "scores":
+		os.Exit(runScores(opts, args[1:]))
+	case "replay":
+		os.Exit(runReplay(opts, args[1:]))
+	case "skins":
+		os.Exit(runSkins(opts, args[1:]))
+	case "migrate":
+		os.Exit(runMigrate(opts, args[1:]))
+	case "help":
+		printUsage()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+		printUsage()
+		os.Exit(2)
+	}
+}
+
+func printUsage() {
+	fmt.Fprintf(os.Stderr, usage, config.DefaultPath())
+}
+
+// newFlagSet создаёт набор флагов команды, в котором есть и общие флаги.
+func newFlagSet(name string, opts *options) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = printUsage
+	opts.bind(fs)
+	return fs
+}
+
+// newApp настраивает логгер и загружает конфигурацию: файл, переменные окружения,
+// затем флаги настроек до команды и из sets. Ошибку newApp уже записала в лог.
+func newApp(opts options, defaultLevel slog.Level, sets ...*flag.FlagSet) (*app, error) {
+	level := defaultLevel
+	if opts.logLevel != "" {
+		if err := level.UnmarshalText([]byte(opts.logLevel)); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid log level %q: expected debug, info, warn or error\n", opts.logLevel)
+			return nil, err
+		}
+	}
+
+	handlerOpts := &slog.HandlerOptions{
+		Level: level,
 		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
 			// Форматируем время для удобства
 			if a.Key == slog.TimeKey {
@@ -25,54 +126,108 @@ func main() {
 			return a
 		},
 	}
-	logger := slog.New(slog.NewTextHandler(os.Stdout, opts))
+	// лог идёт в stderr, чтобы не смешиваться с выводом команд
+	logger := slog.New(slog.NewTextHandler(os.Stderr, handlerOpts))
+
+	if err := godotenv.Load(); err != nil {
+		logger.Debug("failed to load from .env", "error", err)
+	}
 
-	// 1. Загружаем конфигурацию
-	cfg := config.LoadConfig()
+	cfg, err := config.LoadConfig(opts.configPath)
+	if err != nil {
+		logger.Error("failed to load config", "error", err)
+		return nil, err
+	}
+	for _, fs := range append([]*flag.FlagSet{flag.CommandLine}, sets...) {
+		if err := cfg.ApplyFlags(fs); err != nil {
+			logger.Error("invalid command line settings", "error", err)
+			return nil, err
+		}
+	}
+	if opts.db != "" {
+		cfg.DatabaseURL = opts.db
+		cfg.Storage = config.StoragePostgres
+	}
 	cfg.SetLogger(logger)
+	logger.Info("config loaded", "path", cfg.Path)
+	return &app{cfg: cfg, logger: logger}, nil
+}
+
+// runGame открывает окно игры; start, если задан, сразу переводит игру из главного меню
+// в нужную сцену.
+func runGame(opts options, sets []*flag.FlagSet, start func(a *app, g *game.Game) error) int {
+	a, err := newApp(opts, slog.LevelDebug, sets...)
+	if err != nil {
+		return 1
+	}
+	cfg, logger := a.cfg, a.logger
 
-	// 2. Загружаем ассеты (картинки, шрифты)
-	assets, err := assets.Load("snake")
+	// 1. Загружаем ассеты (картинки, шрифты); неисправный скин заменяется скином по умолчанию
+	skins, err := loadSkins(a)
+	if err != nil {
+		return 1
+	}
+	skin, err := skins.Resolve(cfg.Skin)
+	if skin == nil {
+		logger.Error("no usable skin", "error", err)
+		return 1
+	} else if err != nil {
+		logger.Warn("using default skin", "skin", skin.ID, "reason", err)
+	}
+	assets, err := assets.Load(skin)
 	if err != nil {
 		logger.Error("Failed to initialize assets", "err", err)
-		os.Exit(1)
+		return 1
 	} else {
 		logger.Info("Assets successfully loaded")
 	}
 
-	if err := godotenv.Load(); err != nil {
-		logger.Warn("failed to load from .env", "error", err)
+	// 2. Открываем хранилище рекордов
+	if cfg.Storage == config.StoragePostgres {
+		logger.Info("using postgres storage")
+	} else {
+		logger.Info("using local storage", "storage", cfg.Storage, "path", cfg.RecordsFile)
 	}
-
-	var repo storage.Repository
-
-	connStr := os.Getenv("DATABASE_URL")
-	if connStr == "" {
-		logger.Warn("DATABASE_URL environment variable is not set. Running without database.")
+	repo, err := storage.Open(cfg, logger)
+	if err != nil {
+		logger.Error("failed to open records storage", "storage", cfg.Storage, "error", err)
+		return 1
 	}
+	defer repo.Close()
 
-	if connStr != "" {
-		repo, err = storage.NewPostgresRepository(connStr, logger)
-		if err != nil {
-			logger.Error("failed to connect to database", "error", err)
-			os.Exit(1)
-		}
-		defer repo.Close()
+	// 3. Включаем звук; если он не готов, игра идёт молча
+	sounds, err := audio.New(cfg)
+	if err != nil {
+		logger.Warn("audio is off", "backend", cfg.Audio, "error", err)
+		sounds = &audio.NullPlayer{}
+	} else {
+		logger.Info("audio initialized", "backend", cfg.Audio, "volume", cfg.Volume, "muted", cfg.Muted)
 	}
 
-	g, err := game.NewGame(cfg, assets, repo)
+	g, err := game.NewGame(cfg, assets, skins, sounds, repo)
 	if err != nil {
 		logger.Error("failed to create game", "err", err)
-		os.Exit(1)
+		return 1
 	} else {
 		logger.Info("Game successfully initialized")
 	}
+	if start != nil {
+		if err := start(a, g); err != nil {
+			logger.Error("failed to start", "error", err)
+			return 1
+		}
+	}
 
 	// 4. Настраиваем и запускаем окно
-	ebiten.SetWindowSize(cfg.ScreenWidth, cfg.ScreenHeight+cfg.TopBarHeight)
+	// окно открывается размером из настроек, а дальше его можно растягивать: интерфейс
+	// и поле подстраиваются под него в Game.Layout
+	ebiten.SetWindowSize(cfg.ScreenWidth, cfg.WindowHeight())
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 	ebiten.SetWindowTitle("Змейка на Ebitengine")
 
 	if err := ebiten.RunGame(g); err != nil {
 		logger.Error("game finished with error", "error", err)
+		return 1
 	}
+	return 0
 }
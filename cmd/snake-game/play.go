@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"snake-game/internal/config"
+	"snake-game/internal/game"
+	"snake-game/internal/levels"
+	"snake-game/internal/scenes"
+	"snake-game/internal/sim"
+)
+
+// runPlay открывает окно сразу с игрой на уровне из файла; после игры открывается главное меню.
+func runPlay(opts options, args []string) int {
+	fs := newFlagSet("play", &opts)
+	levelPath := fs.String("level", "", "path to level file")
+	seed := fs.Uint64("seed", 0, "world seed (0 - random)")
+	modeName := fs.String("mode", sim.ClassicModeName, "game mode")
+	config.RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *levelPath == "" || fs.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "usage: snake-game play --level FILE [--seed N] [--mode M]")
+		return 2
+	}
+	mode, err := sim.ModeByName(*modeName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	return runGame(opts, []*flag.FlagSet{fs}, func(a *app, g *game.Game) error {
+		level, err := levels.LoadValid(*levelPath)
+		if err != nil {
+			return err
+		}
+		a.logger.Info("starting level from command line", "path", *levelPath, "seed", *seed, "mode", mode.Name())
+		g.StartGame(scenes.GameSetup{
+			Level:   level,
+			Mode:    mode,
+			Players: 1,
+			Seed:    *seed,
+		})
+		return nil
+	})
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"snake-game/internal/core"
+	"snake-game/internal/game"
+	"snake-game/internal/replay"
+)
+
+// runReplay показывает повтор в окне или, с --check, проигрывает его без окна
+// и сверяет результат с записанным.
+func runReplay(opts options, args []string) int {
+	fs := newFlagSet("replay", &opts)
+	check := fs.Bool("check", false, "re-simulate the replay without a window and compare the result with the recorded one")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: snake-game replay [--check] FILE")
+		return 2
+	}
+	path := fs.Arg(0)
+
+	if *check {
+		a, err := newApp(opts, slog.LevelWarn)
+		if err != nil {
+			return 1
+		}
+		return checkReplay(a, path)
+	}
+
+	return runGame(opts, nil, func(a *app, g *game.Game) error {
+		rep, err := replay.Load(path)
+		if err != nil {
+			return err
+		}
+		g.WatchReplay(rep, core.MainMenuState)
+		return nil
+	})
+}
+
+func checkReplay(a *app, path string) int {
+	rep, err := replay.Load(path)
+	if err != nil {
+		a.logger.Error("failed to load replay", "path", path, "error", err)
+		return 1
+	}
+	world, err := rep.NewWorld()
+	if err != nil {
+		a.logger.Error("failed to create replay world", "path", path, "error", err)
+		return 1
+	}
+
+	// запись заканчивается на тике конца игры, дальше мир идти не должен
+	player := replay.NewPlayer(rep)
+	for !world.IsOver() && world.Tick <= rep.Ticks {
+		world.Step(player.Inputs(world.Tick)...)
+	}
+
+	recorded := rep.Scores
+	if len(recorded) == 0 {
+		// повторы до игры вдвоём хранили только счёт первого игрока
+		recorded = []int{rep.Score}
+	}
+	if !world.IsOver() || world.Tick != rep.Ticks || !slices.Equal(world.Scores, recorded) {
+		fmt.Printf("MISMATCH %s: recorded scores %v in %d ticks, simulated %v in %d ticks\n", path, recorded, rep.Ticks, world.Scores, world.Tick)
+		return 1
+	}
+	fmt.Printf("ok %s: level %s, mode %s, scores %v in %d ticks, %s\n", path, rep.Level.Name, world.Mode().Name(), world.Scores, world.Tick, world.DeathCause)
+	return 0
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"snake-game/internal/storage"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+const scoresUsage = "usage: snake-game scores top [--level X] [--player Y] [--mode M] [--limit N] [--format table|json|csv]"
+
+// scoreRow — строка таблицы рекордов в выводе команды.
+type scoreRow struct {
+	Rank        int       `json:"rank"`
+	Player      string    `json:"player"`
+	Score       int       `json:"score"`
+	TimeSeconds float64   `json:"time_seconds"`
+	Level       string    `json:"level"`
+	Mode        string    `json:"mode"`
+	CreatedAt   time.Time `json:"created_at"`
+	ReplayFile  string    `json:"replay_file,omitempty"`
+}
+
+var scoresHeader = []string{"RANK", "PLAYER", "SCORE", "TIME", "LEVEL", "MODE", "DATE"}
+
+func (r scoreRow) cells() []string {
+	return []string{
+		strconv.Itoa(r.Rank),
+		r.Player,
+		strconv.Itoa(r.Score),
+		strconv.FormatFloat(r.TimeSeconds, 'f', 1, 64),
+		r.Level,
+		r.Mode,
+		r.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// runScores выполняет подкоманду scores и возвращает код завершения.
+func runScores(opts options, args []string) int {
+	if len(args) == 0 || args[0] != "top" {
+		fmt.Fprintln(os.Stderr, scoresUsage)
+		return 2
+	}
+
+	fs := newFlagSet("scores top", &opts)
+	level := fs.String("level", "", "level name")
+	player := fs.String("player", "", "player name prefix")
+	mode := fs.String("mode", "", "game mode")
+	limit := fs.Int("limit", 10, "number of records")
+	format := fs.String("format", "table", "output format: table, json or csv")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if *format != "table" && *format != "json" && *format != "csv" {
+		fmt.Fprintf(os.Stderr, "unknown format %q: expected table, json or csv\n", *format)
+		return 2
+	}
+	if *limit < 1 {
+		fmt.Fprintf(os.Stderr, "invalid limit: expected positive value, received %d\n", *limit)
+		return 2
+	}
+
+	a, err := newApp(opts, slog.LevelWarn)
+	if err != nil {
+		return 1
+	}
+	repo, err := storage.Open(a.cfg, a.logger)
+	if err != nil {
+		a.logger.Error("failed to open records storage", "storage", a.cfg.Storage, "error", err)
+		return 1
+	}
+	defer repo.Close()
+
+	// порядок тот же, что на экране рекордов по умолчанию: больший счёт выше, при равном счёте быстрее
+	filter := storage.NewFilter(*player, *level, *mode, false, true, *limit)
+	records, err := repo.GetTopRecords(context.Background(), *filter)
+	if err != nil {
+		a.logger.Error("failed to load records", "error", err)
+		return 1
+	}
+
+	rows := make([]scoreRow, 0, len(records))
+	for i, record := range records {
+		rows = append(rows, scoreRow{
+			Rank:        i + 1,
+			Player:      record.PlayerName,
+			Score:       record.Score,
+			TimeSeconds: record.Time.Seconds(),
+			Level:       record.LevelName,
+			Mode:        record.Mode,
+			CreatedAt:   record.CreatedAt,
+			ReplayFile:  record.ReplayFile,
+		})
+	}
+
+	switch *format {
+	case "json":
+		err = writeScoresJSON(os.Stdout, rows)
+	case "csv":
+		err = writeScoresCSV(os.Stdout, rows)
+	default:
+		err = writeScoresTable(os.Stdout, rows)
+	}
+	if err != nil {
+		a.logger.Error("failed to write scores", "error", err)
+		return 1
+	}
+	return 0
+}
+
+func writeScoresJSON(w io.Writer, rows []scoreRow) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(rows)
+}
+
+func writeScoresCSV(w io.Writer, rows []scoreRow) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append(scoresHeader, "REPLAY")); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writer.Write(append(row.cells(), row.ReplayFile)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeScoresTable(w io.Writer, rows []scoreRow) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	line := func(cells []string) {
+		for _, cell := range cells {
+			fmt.Fprint(writer, cell, "\t")
+		}
+		fmt.Fprintln(writer)
+	}
+
+	line(scoresHeader)
+	for _, row := range rows {
+		line(row.cells())
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"snake-game/internal/assets"
+	"text/tabwriter"
+)
+
+const skinsUsage = "usage: snake-game skins list"
+
+// loadSkins находит встроенные и пользовательские скины и пишет в лог неисправные.
+func loadSkins(a *app) (*assets.Registry, error) {
+	skins, err := assets.NewRegistry(a.cfg.SkinsDir)
+	if skins == nil {
+		a.logger.Error("failed to load skins", "error", err)
+		return nil, err
+	}
+	if err != nil {
+		a.logger.Warn("failed to load user skins", "dir", a.cfg.SkinsDir, "error", err)
+	}
+	for _, skin := range skins.Skins() {
+		if !skin.IsValid() {
+			a.logger.Warn("invalid skin", "skin", skin.ID, "path", skin.Path, "error", skin.Err)
+		}
+	}
+	return skins, nil
+}
+
+// runSkins выполняет подкоманду skins и возвращает код завершения.
+func runSkins(opts options, args []string) int {
+	if len(args) == 0 || args[0] != "list" {
+		fmt.Fprintln(os.Stderr, skinsUsage)
+		return 2
+	}
+	fs := newFlagSet("skins list", &opts)
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	a, err := newApp(opts, slog.LevelError)
+	if err != nil {
+		return 1
+	}
+	skins, err := loadSkins(a)
+	if err != nil {
+		return 1
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tNAME\tAUTHOR\tSOURCE\tSTATUS")
+	for _, skin := range skins.Skins() {
+		source := "built-in"
+		if !skin.IsBuiltin() {
+			source = skin.Path
+		}
+		status := "ok"
+		if !skin.IsValid() {
+			status = "invalid: " + skin.Err.Error()
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", skin.ID, skin.Title(), valueOrDash(skin.Author), source, status)
+	}
+	if err := writer.Flush(); err != nil {
+		a.logger.Error("failed to write skin list", "error", err)
+		return 1
+	}
+	return 0
+}
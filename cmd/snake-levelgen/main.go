@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"snake-game/internal/levelgen"
+	"snake-game/internal/levels"
+	"strings"
+)
+
+func main() {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	seed := flag.Uint64("seed", 0, "generator seed (0 - random)")
+	width := flag.Int("width", 20, "grid width")
+	height := flag.Int("height", 10, "grid height")
+	density := flag.Float64("density", 0.2, "share of wall cells, from 0 to 1")
+	style := flag.String("style", levelgen.StyleScatter, "layout style: "+strings.Join(levelgen.Styles(), ", "))
+	symmetric := flag.Bool("symmetric", false, "make the layout point-symmetric for fair two-player games")
+	name := flag.String("name", "", "level name (default <style>_<seed>)")
+	count := flag.Int("count", 1, "number of levels to generate with seeds seed, seed+1, ...")
+	outDir := flag.String("out", levels.Dir, "directory to write levels to")
+	force := flag.Bool("force", false, "overwrite existing level files")
+	flag.Parse()
+
+	if *count < 1 {
+		fmt.Fprintf(os.Stderr, "invalid number of levels: expected positive value, received %d\n", *count)
+		os.Exit(2)
+	}
+	if *seed == 0 {
+		*seed = rand.Uint64()
+	}
+
+	for i := 0; i < *count; i++ {
+		params := levelgen.Params{
+			Seed:      *seed + uint64(i),
+			Width:     *width,
+			Height:    *height,
+			Density:   *density,
+			Style:     *style,
+			Symmetric: *symmetric,
+			Name:      *name,
+		}
+		if *name != "" && *count > 1 {
+			params.Name = fmt.Sprintf("%s_%d", *name, i+1)
+		}
+
+		level, err := levelgen.Generate(params)
+		if err != nil {
+			logger.Error("failed to generate level", "seed", params.Seed, "error", err)
+			os.Exit(1)
+		}
+
+		path := filepath.Join(*outDir, level.Name+levels.Ext)
+		if _, err := os.Stat(path); err == nil && !*force {
+			logger.Error("level file already exists, use -force to overwrite", "path", path)
+			os.Exit(1)
+		} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+			logger.Error("failed to check level file", "path", path, "error", err)
+			os.Exit(1)
+		}
+
+		if err := levels.Save(path, level); err != nil {
+			logger.Error("failed to save level", "path", path, "error", err)
+			os.Exit(1)
+		}
+		fmt.Println(path)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"os"
+	"os/signal"
+	"snake-game/internal/config"
+	"snake-game/internal/levels"
+	"snake-game/internal/netplay"
+	"snake-game/internal/replay"
+	"snake-game/internal/sim"
+	"time"
+)
+
+func main() {
+	opts := &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				a.Value = slog.StringValue(a.Value.Time().Format(time.RFC3339))
+			}
+			return a
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, opts))
+
+	addr := flag.String("addr", ":7777", "address to listen on")
+	levelPath := flag.String("level", "levels/open_field.json", "path to level file")
+	modeName := flag.String("mode", sim.ClassicModeName, "game mode")
+	players := flag.Int("players", 2, "number of players")
+	seed := flag.Uint64("seed", 0, "world seed (0 - random)")
+	inputDelay := flag.Int("input-delay", netplay.DefaultInputDelay, "ticks clients schedule their input ahead")
+	rounds := flag.Int("rounds", 0, "number of games to host (0 - until interrupted)")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		logger.Error("failed to load config", "error", err)
+		os.Exit(1)
+	}
+	cfg.SetLogger(logger)
+
+	level, err := levels.Load(*levelPath)
+	if err != nil {
+		logger.Error("failed to load level", "path", *levelPath, "error", err)
+		os.Exit(1)
+	}
+	// старты всех игроков, в том числе по умолчанию, должны быть свободны
+	if err := levels.ValidatePlayers(level, *players); err != nil {
+		logger.Error("level is not playable for this number of players", "path", *levelPath, "players", *players, "error", err)
+		os.Exit(1)
+	}
+
+	mode, err := sim.ModeByName(*modeName)
+	if err != nil {
+		logger.Error("invalid game mode", "error", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		logger.Error("failed to listen", "addr", *addr, "error", err)
+		os.Exit(1)
+	}
+	logger.Info("server started", "addr", ln.Addr().String(), "level", level.Name, "mode", mode.Name(), "players", *players)
+
+	for round := 1; *rounds == 0 || round <= *rounds; round++ {
+		gameSeed := *seed
+		if gameSeed == 0 {
+			gameSeed = rand.Uint64()
+		}
+
+		server, err := netplay.NewServer(netplay.ServerConfig{
+			Level:      level,
+			Rules:      sim.RulesFromConfig(cfg),
+			Mode:       mode,
+			Players:    *players,
+			Seed:       gameSeed,
+			InputDelay: *inputDelay,
+		}, logger)
+		if err != nil {
+			logger.Error("failed to create server", "error", err)
+			os.Exit(1)
+		}
+
+		rep, err := server.Run(ctx, ln)
+		if err != nil {
+			if ctx.Err() != nil {
+				logger.Info("server stopped")
+				return
+			}
+			logger.Error("game failed", "round", round, "error", err)
+			os.Exit(1)
+		}
+
+		path, err := rep.Save(replay.Dir)
+		if err != nil {
+			logger.Error("failed to save replay", "error", err)
+		} else {
+			logger.Info("replay saved", "path", path)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package ai
+
+import (
+	"snake-game/internal/core"
+	"snake-game/internal/sim"
+)
+
+// board — клетки, в которые змее нельзя шагнуть на следующем ходу. Хвосты змей
+// не считаются занятыми: к моменту хода они успеют сдвинуться.
+// Столкновения, не смертельные в текущем режиме, препятствиями не считаются.
+type board struct {
+	width, height int
+	wraps         bool
+	blocked       map[core.Position]bool
+}
+
+func newBoard(w *sim.World, player int) *board {
+	mode := w.Mode()
+	b := &board{
+		width:   w.Level.GridWidth,
+		height:  w.Level.GridHeight,
+		wraps:   mode.WrapsBorders(),
+		blocked: make(map[core.Position]bool),
+	}
+
+	if mode.IsLethal(sim.WallCollision) {
+		for _, wall := range w.Level.Walls {
+			b.blocked[wall.Position] = true
+		}
+	}
+	for i, snake := range w.Snakes {
+		if !snake.IsAlive {
+			continue
+		}
+		if i == player && !mode.IsLethal(sim.SelfCollision) || i != player && !mode.IsLethal(sim.SnakeCollision) {
+			continue
+		}
+		for _, segment := range snake.Body[:len(snake.Body)-1] {
+			b.blocked[segment.Position] = true
+		}
+	}
+	return b
+}
+
+// neighbor возвращает клетку по соседству; false — шаг уводит за край поля.
+func (b *board) neighbor(pos core.Position, direction core.Direction) (core.Position, bool) {
+	next := pos.Move(direction)
+	if b.wraps {
+		next.X = (next.X%b.width + b.width) % b.width
+		next.Y = (next.Y%b.height + b.height) % b.height
+	}
+	return next, b.isInside(next)
+}
+
+func (b *board) isInside(pos core.Position) bool {
+	return pos.X >= 0 && pos.X < b.width && pos.Y >= 0 && pos.Y < b.height
+}
+
+func (b *board) isFree(pos core.Position) bool {
+	return !b.blocked[pos]
+}
+
+// step — клетка, в которую змея может безопасно шагнуть в направлении direction.
+func (b *board) step(pos core.Position, direction core.Direction) (core.Position, bool) {
+	next, ok := b.neighbor(pos, direction)
+	return next, ok && b.isFree(next)
+}
+
+// distance — манхэттенское расстояние с учётом замкнутых краёв.
+func (b *board) distance(from, to core.Position) int {
+	dx, dy := abs(from.X-to.X), abs(from.Y-to.Y)
+	if b.wraps {
+		dx, dy = min(dx, b.width-dx), min(dy, b.height-dy)
+	}
+	return dx + dy
+}
+
+// area считает клетки, достижимые из start; поиск останавливается на limit клетках.
+func (b *board) area(start core.Position, limit int) int {
+	visited := map[core.Position]bool{start: true}
+	queue := []core.Position{start}
+	for len(queue) > 0 && len(visited) < limit {
+		pos := queue[0]
+		queue = queue[1:]
+		for _, direction := range directions {
+			next, ok := b.step(pos, direction)
+			if ok && !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return len(visited)
+}
+
+var directions = []core.Direction{core.Up, core.Down, core.Left, core.Right}
+
+// candidates — направления, в которые змея может повернуть; текущее идёт первым,
+// чтобы при равных вариантах змея не виляла.
+func candidates(snake *core.Snake) []core.Direction {
+	result := []core.Direction{snake.Direction}
+	for _, direction := range directions {
+		if direction != snake.Direction && direction != snake.Direction.Opposite() {
+			result = append(result, direction)
+		}
+	}
+	return result
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,237 @@
+package ai
+
+import (
+	"fmt"
+	"snake-game/internal/core"
+	"snake-game/internal/sim"
+)
+
+const (
+	GreedyName      = "greedy"
+	BFSName         = "bfs"
+	HamiltonianName = "hamiltonian"
+)
+
+// Controller управляет змеей игрока вместо человека.
+type Controller interface {
+	Name() string
+	// Next выбирает направление для змеи игрока player по текущему состоянию мира.
+	Next(w *sim.World, player int) core.Direction
+}
+
+// Input превращает решение контроллера в ввод для World.Step — поворот применяется
+// через Snake.SetNextDirection так же, как нажатие клавиши, и попадает в повтор.
+// Бот решает заново каждый тик, поэтому его решение заменяет запланированные повороты,
+// а не встаёт за ними в очередь: иначе змея исполнила бы поворот, от которого бот отказался.
+func Input(c Controller, w *sim.World, player int) sim.Input {
+	snake := w.Snakes[player]
+	if !snake.IsAlive {
+		return sim.Input{}
+	}
+	direction := c.Next(w, player)
+	// решение не изменилось с прошлого тика
+	if direction == snake.PlannedDirection() {
+		return sim.Input{}
+	}
+	// после Replace поворот сверяется с текущим направлением змеи; если бот решил ехать
+	// прямо, поворот отклонится, а передуманный поворот из очереди всё равно забудется
+	return sim.Input{Turns: []core.Direction{direction}, Replace: true}
+}
+
+// Controllers возвращает новые экземпляры всех встроенных стратегий.
+func Controllers() []Controller {
+	return []Controller{
+		Greedy{},
+		BFS{},
+		NewHamiltonian(),
+	}
+}
+
+func ControllerByName(name string) (Controller, error) {
+	for _, controller := range Controllers() {
+		if controller.Name() == name {
+			return controller, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown controller %q", name)
+}
+
+// Greedy идёт к еде по кратчайшему манхэттенскому расстоянию, избегая только
+// клеток, в которые врежется следующим ходом.
+type Greedy struct{}
+
+func (Greedy) Name() string { return GreedyName }
+
+func (Greedy) Next(w *sim.World, player int) core.Direction {
+	b := newBoard(w, player)
+	snake := w.Snakes[player]
+	head := snake.Body[0].Position
+
+	best, bestDistance := snake.Direction, -1
+	for _, direction := range candidates(snake) {
+		next, ok := b.step(head, direction)
+		if !ok {
+			continue
+		}
+		distance := 0
+		if w.Food != nil {
+			distance = b.distance(next, w.Food.Position)
+		}
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = direction, distance
+		}
+	}
+	return best
+}
+
+// BFS ищет кратчайший путь до еды в обход стен и тел змей. Если пути нет,
+// выбирает ход, после которого остаётся больше всего свободного места.
+type BFS struct{}
+
+func (BFS) Name() string { return BFSName }
+
+func (BFS) Next(w *sim.World, player int) core.Direction {
+	b := newBoard(w, player)
+	snake := w.Snakes[player]
+	head := snake.Body[0].Position
+
+	if w.Food != nil {
+		if direction, ok := b.pathTo(head, candidates(snake), w.Food.Position); ok {
+			return direction
+		}
+	}
+	return b.roomiest(head, snake)
+}
+
+// pathTo возвращает первый шаг кратчайшего пути от start до target.
+func (b *board) pathTo(start core.Position, first []core.Direction, target core.Position) (core.Direction, bool) {
+	firstSteps := map[core.Position]core.Direction{}
+	queue := make([]core.Position, 0)
+	for _, direction := range first {
+		next, ok := b.step(start, direction)
+		if ok && next != start {
+			if _, seen := firstSteps[next]; !seen {
+				firstSteps[next] = direction
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	for len(queue) > 0 {
+		pos := queue[0]
+		queue = queue[1:]
+		if pos == target {
+			return firstSteps[pos], true
+		}
+		for _, direction := range directions {
+			next, ok := b.step(pos, direction)
+			if _, seen := firstSteps[next]; ok && !seen && next != start {
+				firstSteps[next] = firstSteps[pos]
+				queue = append(queue, next)
+			}
+		}
+	}
+	return 0, false
+}
+
+// roomiest выбирает безопасный ход с наибольшей достижимой областью.
+func (b *board) roomiest(head core.Position, snake *core.Snake) core.Direction {
+	limit := len(snake.Body) * 4
+	best, bestArea := snake.Direction, -1
+	for _, direction := range candidates(snake) {
+		next, ok := b.step(head, direction)
+		if !ok {
+			continue
+		}
+		if area := b.area(next, limit); area > bestArea {
+			best, bestArea = direction, area
+		}
+	}
+	return best
+}
+
+// Hamiltonian ведёт змею по гамильтонову циклу, проходящему через все клетки поля,
+// поэтому змея никогда в себя не врезается. Цикл существует только на поле без стен
+// с чётной стороной; на остальных уровнях и пока змея не вошла в цикл
+// используется BFS.
+type Hamiltonian struct {
+	level *core.Level
+	cells []core.Position
+	order map[core.Position]int
+}
+
+func NewHamiltonian() *Hamiltonian {
+	return &Hamiltonian{}
+}
+
+func (h *Hamiltonian) Name() string { return HamiltonianName }
+
+func (h *Hamiltonian) Next(w *sim.World, player int) core.Direction {
+	if h.level != w.Level {
+		h.level = w.Level
+		h.cells = buildCycle(w.Level)
+		h.order = make(map[core.Position]int, len(h.cells))
+		for i, cell := range h.cells {
+			h.order[cell] = i
+		}
+	}
+
+	snake := w.Snakes[player]
+	head := snake.Body[0].Position
+	index, ok := h.order[head]
+	if !ok {
+		return BFS{}.Next(w, player)
+	}
+
+	next := h.cells[(index+1)%len(h.cells)]
+	direction := core.GetDirection(next, head)
+	if direction == snake.Direction.Opposite() || !newBoard(w, player).isFree(next) {
+		return BFS{}.Next(w, player)
+	}
+	return direction
+}
+
+// buildCycle строит цикл змейкой: первая строка целиком, затем зигзаг по остальным
+// строкам без первого столбца и возврат вверх по первому столбцу. Нужна чётная высота,
+// иначе поле транспонируется; nil — цикла нет.
+func buildCycle(level *core.Level) []core.Position {
+	width, height := level.GridWidth, level.GridHeight
+	if len(level.Walls) > 0 || width < 2 || height < 2 {
+		return nil
+	}
+
+	transpose := false
+	if height%2 != 0 {
+		if width%2 != 0 {
+			return nil
+		}
+		width, height = height, width
+		transpose = true
+	}
+
+	cells := make([]core.Position, 0, width*height)
+	for x := 0; x < width; x++ {
+		cells = append(cells, core.Position{X: x, Y: 0})
+	}
+	for y := 1; y < height; y++ {
+		if y%2 == 1 {
+			for x := width - 1; x >= 1; x-- {
+				cells = append(cells, core.Position{X: x, Y: y})
+			}
+		} else {
+			for x := 1; x < width; x++ {
+				cells = append(cells, core.Position{X: x, Y: y})
+			}
+		}
+	}
+	for y := height - 1; y >= 1; y-- {
+		cells = append(cells, core.Position{X: 0, Y: y})
+	}
+
+	if transpose {
+		for i, cell := range cells {
+			cells[i] = core.Position{X: cell.Y, Y: cell.X}
+		}
+	}
+	return cells
+}
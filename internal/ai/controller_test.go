@@ -0,0 +1,52 @@
+package ai
+
+import (
+	"snake-game/internal/core"
+	"snake-game/internal/sim"
+	"testing"
+)
+
+// scripted выдаёт заранее заданные решения по тикам, а между ними не меняет решения.
+type scripted map[int]core.Direction
+
+func (scripted) Name() string { return "scripted" }
+
+func (s scripted) Next(w *sim.World, player int) core.Direction {
+	if direction, ok := s[w.Tick]; ok {
+		return direction
+	}
+	return w.Snakes[player].PlannedDirection()
+}
+
+// TestInputReplacesAbandonedTurn — бот решил повернуть вверх, а до шага змеи передумал:
+// змея должна исполнить только последнее решение.
+func TestInputReplacesAbandonedTurn(t *testing.T) {
+	tests := []struct {
+		name     string
+		bot      scripted
+		wantHead core.Position
+	}{
+		{name: "keeps the turn", bot: scripted{0: core.Up}, wantHead: core.Position{X: 5, Y: 3}},
+		{name: "back to straight", bot: scripted{0: core.Up, 1: core.Right}, wantHead: core.Position{X: 6, Y: 4}},
+		{name: "other turn", bot: scripted{0: core.Up, 1: core.Down}, wantHead: core.Position{X: 5, Y: 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level := core.NewLevel("open", 10, 8, nil)
+			rules := sim.Rules{InitialSnakeLen: 3, InitialSpeed: 3, MaxSpeed: 1, TurnQueue: sim.TurnQueueSize}
+			w, err := sim.NewWorld(level, rules, sim.ClassicMode{}, 1, 1)
+			if err != nil {
+				t.Fatalf("NewWorld: %v", err)
+			}
+
+			for w.Moves[0] == 0 {
+				w.Step(Input(tt.bot, w, 0))
+			}
+
+			if head := w.Snakes[0].Body[0].Position; head != tt.wantHead {
+				t.Fatalf("head at %v after the first move, want %v", head, tt.wantHead)
+			}
+		})
+	}
+}
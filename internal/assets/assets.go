@@ -2,10 +2,11 @@ package assets
 
 import (
 	"embed"
+	"fmt"
 	"image"
 	"image/color"
 	_ "image/png"
-	"path/filepath"
+	"io/fs"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"golang.org/x/image/font"
@@ -15,21 +16,28 @@ import (
 //go:embed images/* fonts/*
 var assetsFS embed.FS
 
-type Assets struct {
+// Sprites — картинки скина, которыми рисуется поле.
+type Sprites struct {
 	SnakeHead       *ebiten.Image
 	SnakeBody       *ebiten.Image
 	SnakeBodyCorner *ebiten.Image
 	SnakeTail       *ebiten.Image
 	Apple           *ebiten.Image
 	Wall            *ebiten.Image
+}
+
+type Assets struct {
+	Sprites
+	// Skin — скин, спрайты которого сейчас загружены
+	Skin *Skin
 
 	UIFont     font.Face
 	TitleFont  font.Face
 	WhitePixel *ebiten.Image
 }
 
-func loadImage(path string) (*ebiten.Image, error) {
-	file, err := assetsFS.Open(path)
+func loadImage(files fs.FS, path string) (*ebiten.Image, error) {
+	file, err := files.Open(path)
 	if err != nil {
 		return nil, err
 	}
@@ -37,40 +45,50 @@ func loadImage(path string) (*ebiten.Image, error) {
 
 	img, _, err := image.Decode(file)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
 	}
 	return ebiten.NewImageFromImage(img), nil
 }
 
-func Load(skin string) (*Assets, error) {
-	var err error
-	assets := &Assets{}
-
-	skinPath := filepath.Join("images", skin)
-
-	// --- Загрузка изображений (без изменений) ---
-	assets.SnakeHead, err = loadImage(filepath.Join(skinPath, "head.png"))
-	if err != nil {
-		return nil, err
-	}
-	assets.SnakeBody, err = loadImage(filepath.Join(skinPath, "body.png"))
-	if err != nil {
-		return nil, err
+// LoadSprites загружает картинки исправного скина.
+func LoadSprites(skin *Skin) (*Sprites, error) {
+	if !skin.IsValid() {
+		return nil, fmt.Errorf("skin %s is invalid: %w", skin.ID, skin.Err)
 	}
-	assets.SnakeBodyCorner, err = loadImage(filepath.Join(skinPath, "body_corner.png"))
-	if err != nil {
-		return nil, err
+
+	sprites := &Sprites{}
+	targets := map[string]**ebiten.Image{
+		SpriteHead:       &sprites.SnakeHead,
+		SpriteBody:       &sprites.SnakeBody,
+		SpriteBodyCorner: &sprites.SnakeBodyCorner,
+		SpriteTail:       &sprites.SnakeTail,
+		SpriteFood:       &sprites.Apple,
+		SpriteWall:       &sprites.Wall,
 	}
-	assets.SnakeTail, err = loadImage(filepath.Join(skinPath, "tail.png"))
-	if err != nil {
-		return nil, err
+	for _, sprite := range RequiredSprites() {
+		img, err := loadImage(skin.files, sprite)
+		if err != nil {
+			return nil, fmt.Errorf("skin %s: %w", skin.ID, err)
+		}
+		*targets[sprite] = img
 	}
-	assets.Apple, err = loadImage(filepath.Join(skinPath, "food.png"))
+	return sprites, nil
+}
+
+// UseSkin заменяет спрайты на спрайты skin; сцены подхватывают их со следующего кадра.
+func (assets *Assets) UseSkin(skin *Skin) error {
+	sprites, err := LoadSprites(skin)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	assets.Wall, err = loadImage(filepath.Join(skinPath, "wall.png"))
-	if err != nil {
+	assets.Sprites = *sprites
+	assets.Skin = skin
+	return nil
+}
+
+func Load(skin *Skin) (*Assets, error) {
+	assets := &Assets{}
+	if err := assets.UseSkin(skin); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,213 @@
+package assets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultSkin — скин, который используется, если выбранный не найден или неисправен
+	DefaultSkin = "snake"
+	// ManifestFile — описание скина в его каталоге; у пользовательских скинов обязательно
+	ManifestFile = "skin.json"
+	// builtinDir — каталог встроенных скинов внутри embed.FS
+	builtinDir = "images"
+)
+
+// Файлы спрайтов, которые обязан содержать каждый скин
+const (
+	SpriteHead       = "head.png"
+	SpriteBody       = "body.png"
+	SpriteBodyCorner = "body_corner.png"
+	SpriteTail       = "tail.png"
+	SpriteFood       = "food.png"
+	SpriteWall       = "wall.png"
+)
+
+func RequiredSprites() []string {
+	return []string{SpriteHead, SpriteBody, SpriteBodyCorner, SpriteTail, SpriteFood, SpriteWall}
+}
+
+// Manifest — содержимое skin.json.
+type Manifest struct {
+	Name        string `json:"name"`
+	Author      string `json:"author,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Skin — набор спрайтов, встроенный в игру или лежащий в каталоге пользователя.
+// Err — почему скином нельзя пользоваться: нет манифеста или какого-то спрайта.
+type Skin struct {
+	// ID — имя каталога скина; под ним скин хранится в настройках
+	ID string
+	Manifest
+	// Path — каталог пользовательского скина; пусто у встроенных
+	Path string
+	Err  error
+
+	files fs.FS
+}
+
+func (s *Skin) IsBuiltin() bool {
+	return s.Path == ""
+}
+
+func (s *Skin) IsValid() bool {
+	return s.Err == nil
+}
+
+// Title — имя скина для показа: из манифеста, а без него — ID.
+func (s *Skin) Title() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.ID
+}
+
+// Registry — все известные скины: сначала встроенные, затем пользовательские, по ID.
+type Registry struct {
+	skins []*Skin
+}
+
+// NewRegistry находит встроенные скины и скины в userDir (каталог на скин).
+// Отсутствие userDir не ошибка; неисправные скины попадают в реестр со своей ошибкой.
+func NewRegistry(userDir string) (*Registry, error) {
+	registry := &Registry{}
+
+	builtin, err := fs.ReadDir(assetsFS, builtinDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list built-in skins: %w", err)
+	}
+	for _, entry := range builtin {
+		if !entry.IsDir() {
+			continue
+		}
+		files, err := fs.Sub(assetsFS, path.Join(builtinDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open built-in skin %s: %w", entry.Name(), err)
+		}
+		skin := &Skin{ID: entry.Name(), files: files}
+		skin.Err = skin.load(false)
+		registry.skins = append(registry.skins, skin)
+	}
+
+	if userDir == "" {
+		return registry, nil
+	}
+	user, err := os.ReadDir(userDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return registry, nil
+	}
+	if err != nil {
+		return registry, fmt.Errorf("failed to list user skins in %s: %w", userDir, err)
+	}
+	userSkins := make([]*Skin, 0, len(user))
+	for _, entry := range user {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(userDir, entry.Name())
+		skin := &Skin{ID: entry.Name(), Path: dir, files: os.DirFS(dir)}
+		if taken, ok := registry.Skin(skin.ID); ok && taken.IsBuiltin() {
+			skin.Err = fmt.Errorf("skin id %s is taken by a built-in skin, rename the directory", skin.ID)
+		} else {
+			skin.Err = skin.load(true)
+		}
+		userSkins = append(userSkins, skin)
+	}
+	sort.Slice(userSkins, func(i, j int) bool {
+		return userSkins[i].ID < userSkins[j].ID
+	})
+	registry.skins = append(registry.skins, userSkins...)
+	return registry, nil
+}
+
+// Skins возвращает все скины, включая неисправные.
+func (r *Registry) Skins() []*Skin {
+	return r.skins
+}
+
+func (r *Registry) Skin(id string) (*Skin, bool) {
+	for _, skin := range r.skins {
+		if skin.ID == id {
+			return skin, true
+		}
+	}
+	return nil, false
+}
+
+// Resolve возвращает скин id, а если его нет или он неисправен — скин по умолчанию
+// вместе с ошибкой, объясняющей замену.
+func (r *Registry) Resolve(id string) (*Skin, error) {
+	skin, ok := r.Skin(id)
+	var reason error
+	switch {
+	case !ok:
+		reason = fmt.Errorf("skin %q not found", id)
+	case !skin.IsValid():
+		reason = fmt.Errorf("skin %q is invalid: %w", id, skin.Err)
+	default:
+		return skin, nil
+	}
+
+	fallback, ok := r.Skin(DefaultSkin)
+	if !ok || !fallback.IsValid() {
+		return nil, fmt.Errorf("%w; default skin %q is not available either", reason, DefaultSkin)
+	}
+	return fallback, reason
+}
+
+// load читает манифест и проверяет, что все спрайты на месте, читаются и квадратные.
+func (s *Skin) load(requireManifest bool) error {
+	data, err := fs.ReadFile(s.files, ManifestFile)
+	switch {
+	case errors.Is(err, fs.ErrNotExist) && requireManifest:
+		return fmt.Errorf("missing %s", ManifestFile)
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("failed to read %s: %w", ManifestFile, err)
+	default:
+		if err := json.Unmarshal(data, &s.Manifest); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", ManifestFile, err)
+		}
+	}
+
+	problems := make([]string, 0)
+	for _, sprite := range RequiredSprites() {
+		if problem := s.checkSprite(sprite); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func (s *Skin) checkSprite(sprite string) string {
+	file, err := s.files.Open(sprite)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "missing " + sprite
+	}
+	if err != nil {
+		return fmt.Sprintf("%s: %v", sprite, err)
+	}
+	defer file.Close()
+
+	config, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return fmt.Sprintf("%s is not a PNG image", sprite)
+	}
+	if config.Width == 0 || config.Width != config.Height {
+		return fmt.Sprintf("%s is %dx%d, expected a square sprite", sprite, config.Width, config.Height)
+	}
+	return ""
+}
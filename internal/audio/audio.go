@@ -0,0 +1,80 @@
+package audio
+
+import (
+	"embed"
+	"fmt"
+	"snake-game/internal/config"
+)
+
+//go:embed sounds/*.wav music/*.wav
+var audioFS embed.FS
+
+// Sound — короткий звуковой эффект.
+type Sound string
+
+const (
+	SoundEat     Sound = "eat"
+	SoundTurn    Sound = "turn"
+	SoundSpeedUp Sound = "speed_up"
+	SoundDeath   Sound = "death"
+	SoundClick   Sound = "click"
+)
+
+func Sounds() []Sound {
+	return []Sound{SoundEat, SoundTurn, SoundSpeedUp, SoundDeath, SoundClick}
+}
+
+// Track — фоновая музыка, которая играет по кругу.
+type Track string
+
+const (
+	// TrackNone — тишина: текущая музыка останавливается
+	TrackNone Track = ""
+	TrackMenu Track = "menu"
+	TrackGame Track = "game"
+)
+
+func Tracks() []Track {
+	return []Track{TrackMenu, TrackGame}
+}
+
+// Player проигрывает звуки игры. Громкость от 0 до 1; без звука эффекты не слышны,
+// а музыка продолжает идти беззвучно, чтобы после включения не начинаться сначала.
+type Player interface {
+	Play(sound Sound)
+	// PlayMusic включает track; если он уже играет, ничего не меняется
+	PlayMusic(track Track)
+	SetVolume(volume float64)
+	SetMuted(muted bool)
+}
+
+// New создаёт проигрыватель, выбранный в cfg.Audio, с громкостью из настроек.
+func New(cfg *config.Config) (Player, error) {
+	var player Player
+	switch cfg.Audio {
+	case config.AudioOff:
+		player = &NullPlayer{}
+	case config.AudioEbiten:
+		ebitenPlayer, err := NewEbitenPlayer()
+		if err != nil {
+			return nil, err
+		}
+		player = ebitenPlayer
+	default:
+		return nil, fmt.Errorf("unknown audio backend %q: expected %s or %s", cfg.Audio, config.AudioEbiten, config.AudioOff)
+	}
+	player.SetVolume(cfg.Volume)
+	player.SetMuted(cfg.Muted)
+	return player, nil
+}
+
+// NullPlayer ничего не проигрывает: для запуска без звуковой карты и для проверок без окна.
+type NullPlayer struct{}
+
+func (*NullPlayer) Play(Sound) {}
+
+func (*NullPlayer) PlayMusic(Track) {}
+
+func (*NullPlayer) SetVolume(float64) {}
+
+func (*NullPlayer) SetMuted(bool) {}
@@ -0,0 +1,126 @@
+package audio
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+	"io"
+)
+
+const (
+	sampleRate = 44100
+	// musicVolume — насколько музыка тише эффектов при той же громкости
+	musicVolume = 0.5
+)
+
+// EbitenPlayer проигрывает звуки через аудио Ebitengine. Эффекты декодируются заранее целиком,
+// музыка декодируется по ходу проигрывания.
+type EbitenPlayer struct {
+	context *audio.Context
+	// sounds — эффекты в формате контекста: 16 бит, стерео
+	sounds map[Sound][]byte
+	music  map[Track]*audio.Player
+	track  Track
+
+	volume float64
+	muted  bool
+}
+
+func NewEbitenPlayer() (*EbitenPlayer, error) {
+	p := &EbitenPlayer{
+		context: audio.NewContext(sampleRate),
+		sounds:  make(map[Sound][]byte),
+		music:   make(map[Track]*audio.Player),
+		volume:  1,
+	}
+	for _, sound := range Sounds() {
+		stream, err := decode("sounds/" + string(sound) + ".wav")
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(stream)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode sound %s: %w", sound, err)
+		}
+		p.sounds[sound] = data
+	}
+	for _, track := range Tracks() {
+		stream, err := decode("music/" + string(track) + ".wav")
+		if err != nil {
+			return nil, err
+		}
+		player, err := p.context.NewPlayer(audio.NewInfiniteLoop(stream, stream.Length()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create music player %s: %w", track, err)
+		}
+		p.music[track] = player
+	}
+	return p, nil
+}
+
+func (p *EbitenPlayer) Play(sound Sound) {
+	data, ok := p.sounds[sound]
+	if !ok || p.muted || p.volume == 0 {
+		return
+	}
+	// эффекты могут накладываться, поэтому у каждого свой проигрыватель
+	player := p.context.NewPlayerFromBytes(data)
+	player.SetVolume(p.volume)
+	player.Play()
+}
+
+func (p *EbitenPlayer) PlayMusic(track Track) {
+	if track == p.track {
+		return
+	}
+	if current, ok := p.music[p.track]; ok {
+		current.Pause()
+	}
+	p.track = track
+
+	player, ok := p.music[track]
+	if !ok {
+		return
+	}
+	player.SetVolume(p.musicVolume())
+	// трек сцены всегда начинается сначала; ошибка перемотки не мешает играть дальше
+	_ = player.Rewind()
+	player.Play()
+}
+
+func (p *EbitenPlayer) SetVolume(volume float64) {
+	p.volume = min(max(volume, 0), 1)
+	p.updateMusicVolume()
+}
+
+func (p *EbitenPlayer) SetMuted(muted bool) {
+	p.muted = muted
+	p.updateMusicVolume()
+}
+
+func (p *EbitenPlayer) musicVolume() float64 {
+	if p.muted {
+		return 0
+	}
+	return p.volume * musicVolume
+}
+
+func (p *EbitenPlayer) updateMusicVolume() {
+	for _, player := range p.music {
+		player.SetVolume(p.musicVolume())
+	}
+}
+
+// decode читает встроенный WAV и переводит его в частоту контекста.
+func decode(path string) (*wav.Stream, error) {
+	data, err := audioFS.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	stream, err := wav.DecodeWithSampleRate(sampleRate, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return stream, nil
+}
@@ -0,0 +1,182 @@
+package campaign
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"snake-game/internal/core"
+	"snake-game/internal/levels"
+	"snake-game/internal/sim"
+	"sort"
+	"strings"
+)
+
+const (
+	// Dir — каталог с наборами уровней: каждый подкаталог — отдельная кампания
+	Dir = "campaigns"
+	// ManifestFile — необязательный файл набора с порядком уровней и целями
+	ManifestFile = "pack.json"
+	// DefaultTargetScore — цель уровня, если манифест её не задаёт или манифеста нет
+	DefaultTargetScore = 10
+)
+
+// Manifest — содержимое pack.json. Без манифеста уровни набора идут по имени файла.
+type Manifest struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Mode        string          `json:"mode,omitempty"`
+	Levels      []ManifestLevel `json:"levels"`
+}
+
+type ManifestLevel struct {
+	// File — путь к уровню относительно каталога набора
+	File        string `json:"file"`
+	TargetScore int    `json:"target_score,omitempty"`
+}
+
+// Pack — упорядоченный набор уровней; чтобы открыть следующий уровень,
+// нужно набрать на предыдущем не меньше его TargetScore.
+type Pack struct {
+	// ID — имя каталога набора, под ним хранится прогресс
+	ID          string
+	Name        string
+	Description string
+	Mode        sim.GameMode
+	Stages      []Stage
+}
+
+// Stage — уровень кампании. Err — причина, по которой в уровень нельзя играть.
+type Stage struct {
+	Path        string
+	TargetScore int
+	Level       *core.Level
+	Err         error
+}
+
+// ID — имя файла уровня без расширения, под ним хранится лучший счёт.
+func (s Stage) ID() string {
+	return strings.TrimSuffix(filepath.Base(s.Path), levels.Ext)
+}
+
+func (s Stage) IsValid() bool {
+	return s.Err == nil
+}
+
+// LoadPack читает набор из каталога dir: по манифесту, если он есть, иначе все уровни по имени файла.
+func LoadPack(dir string) (*Pack, error) {
+	manifest, err := loadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	mode, err := sim.ModeByName(manifest.Mode)
+	if err != nil {
+		return nil, fmt.Errorf("pack %s: %w", dir, err)
+	}
+	pack := &Pack{
+		ID:          filepath.Base(dir),
+		Name:        manifest.Name,
+		Description: manifest.Description,
+		Mode:        mode,
+	}
+	if pack.Name == "" {
+		pack.Name = pack.ID
+	}
+
+	for _, entry := range manifest.Levels {
+		path := filepath.Join(dir, filepath.FromSlash(entry.File))
+		stage := Stage{Path: path, TargetScore: entry.TargetScore}
+		if stage.TargetScore <= 0 {
+			stage.TargetScore = DefaultTargetScore
+		}
+		stage.Level, stage.Err = levels.LoadValid(path)
+		pack.Stages = append(pack.Stages, stage)
+	}
+	if len(pack.Stages) == 0 {
+		return nil, fmt.Errorf("pack %s has no levels", dir)
+	}
+	return pack, nil
+}
+
+// loadManifest читает pack.json или, если его нет, составляет манифест из файлов каталога.
+func loadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return directoryManifest(dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pack manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse pack manifest %s: %w", dir, err)
+	}
+	return &manifest, nil
+}
+
+func directoryManifest(dir string) (*Manifest, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pack directory: %w", err)
+	}
+	manifest := &Manifest{}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), levels.Ext) {
+			continue
+		}
+		manifest.Levels = append(manifest.Levels, ManifestLevel{File: file.Name()})
+	}
+	return manifest, nil
+}
+
+// ScanPacks загружает все наборы из root по имени каталога. Наборы, которые не удалось
+// загрузить, пропускаются, их ошибки возвращаются вместе с остальными наборами.
+func ScanPacks(root string) ([]*Pack, error) {
+	dirs, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read campaigns directory: %w", err)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		return dirs[i].Name() < dirs[j].Name()
+	})
+
+	packs := make([]*Pack, 0, len(dirs))
+	var errs []error
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		pack, err := LoadPack(filepath.Join(root, dir.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		packs = append(packs, pack)
+	}
+	return packs, errors.Join(errs...)
+}
+
+// IsCompleted — набрал ли игрок на уровне stage его цель.
+func (p *Pack) IsCompleted(progress *Progress, player string, stage int) bool {
+	best, ok := progress.Best(player, p.ID, p.Stages[stage].ID())
+	return ok && best >= p.Stages[stage].TargetScore
+}
+
+// IsUnlocked — открыт ли уровень: первый открыт всегда, остальные — после прохождения предыдущего.
+func (p *Pack) IsUnlocked(progress *Progress, player string, stage int) bool {
+	return stage == 0 || p.IsCompleted(progress, player, stage-1)
+}
+
+// CompletedCount — сколько уровней набора игрок прошёл.
+func (p *Pack) CompletedCount(progress *Progress, player string) int {
+	count := 0
+	for stage := range p.Stages {
+		if p.IsCompleted(progress, player, stage) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,115 @@
+package campaign
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Progress — лучшие результаты игроков на уровнях кампаний, хранится в JSON-файле.
+type Progress struct {
+	path string
+	data progressFile
+}
+
+type progressFile struct {
+	// LastPlayer — имя, под которым играли в последний раз; подставляется при входе в кампанию
+	LastPlayer string `json:"last_player,omitempty"`
+	// Players[игрок][набор] — результаты игрока в наборе
+	Players map[string]map[string]*packProgress `json:"players"`
+}
+
+type packProgress struct {
+	// Best[уровень] — лучший счёт на уровне
+	Best map[string]int `json:"best"`
+}
+
+// LoadProgress читает прогресс из path; отсутствующий файл означает пустой прогресс.
+func LoadProgress(path string) (*Progress, error) {
+	progress := &Progress{
+		path: path,
+		data: progressFile{Players: make(map[string]map[string]*packProgress)},
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return progress, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read campaign progress: %w", err)
+	}
+	if len(data) == 0 {
+		return progress, nil
+	}
+	if err := json.Unmarshal(data, &progress.data); err != nil {
+		return nil, fmt.Errorf("failed to parse campaign progress %s: %w", path, err)
+	}
+	if progress.data.Players == nil {
+		progress.data.Players = make(map[string]map[string]*packProgress)
+	}
+	return progress, nil
+}
+
+// playerKey приводит имя к виду, под которым хранится прогресс: без пробелов по краям и без учёта регистра.
+func playerKey(player string) string {
+	return strings.ToUpper(strings.TrimSpace(player))
+}
+
+func (p *Progress) LastPlayer() string {
+	return p.data.LastPlayer
+}
+
+// Best возвращает лучший счёт игрока на уровне; false — игрок его ещё не проходил.
+func (p *Progress) Best(player, pack, stage string) (int, bool) {
+	packs, ok := p.data.Players[playerKey(player)]
+	if !ok || packs[pack] == nil {
+		return 0, false
+	}
+	best, ok := packs[pack].Best[stage]
+	return best, ok
+}
+
+// Record запоминает результат игрока на уровне stage набора pack и возвращает true,
+// если этим результатом игрок впервые прошёл уровень.
+func (p *Progress) Record(player string, pack *Pack, stage, score int) bool {
+	wasCompleted := pack.IsCompleted(p, player, stage)
+
+	key := playerKey(player)
+	p.data.LastPlayer = strings.TrimSpace(player)
+	packs, ok := p.data.Players[key]
+	if !ok {
+		packs = make(map[string]*packProgress)
+		p.data.Players[key] = packs
+	}
+	if packs[pack.ID] == nil {
+		packs[pack.ID] = &packProgress{Best: make(map[string]int)}
+	}
+
+	stageID := pack.Stages[stage].ID()
+	if best, ok := packs[pack.ID].Best[stageID]; !ok || score > best {
+		packs[pack.ID].Best[stageID] = score
+	}
+	return !wasCompleted && pack.IsCompleted(p, player, stage)
+}
+
+// Save записывает прогресс через временный файл, чтобы не потерять его при сбое.
+func (p *Progress) Save() error {
+	data, err := json.MarshalIndent(&p.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal campaign progress: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(p.path), 0755); err != nil {
+		return fmt.Errorf("failed to create campaign progress directory: %w", err)
+	}
+	tmpPath := p.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write campaign progress: %w", err)
+	}
+	if err := os.Rename(tmpPath, p.path); err != nil {
+		return fmt.Errorf("failed to replace campaign progress: %w", err)
+	}
+	return nil
+}
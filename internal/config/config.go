@@ -1,34 +1,265 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+const (
+	StoragePostgres = "postgres"
+	StorageFile     = "file"
+	StorageMemory   = "memory"
+)
+
+const (
+	AudioEbiten = "ebiten"
+	// AudioOff — без звука и без обращения к звуковой карте
+	AudioOff = "off"
 )
 
 type Config struct {
-	ScreenWidth           int
-	ScreenHeight          int
-	TopBarHeight          int
-	TileSize              int
-	InitialSnakeLen       int
-	InitialSpeed          int
-	SpeedIncreaseInterval int
-	SpeedIncreaseAmount   int
-	MaxSpeed              int
-	Logger                *slog.Logger
-}
-
-func LoadConfig() *Config {
-	return &Config{
-		ScreenWidth:           2400,
-		ScreenHeight:          1200,
-		TopBarHeight:          30,
-		TileSize:              120,
-		InitialSnakeLen:       2,
-		InitialSpeed:          30,
-		SpeedIncreaseInterval: 5,
-		SpeedIncreaseAmount:   5,
-		MaxSpeed:              5,
+	Settings
+	TopBarHeight int
+
+	Storage     string
+	DatabaseURL string
+	RecordsFile string
+
+	CampaignProgressFile string
+	// SkinsDir — каталог пользовательских скинов, по подкаталогу на скин
+	SkinsDir string
+	// Audio — через что играет звук: AudioEbiten или AudioOff
+	Audio string
+
+	// Path — файл конфигурации, в который экран настроек сохраняет изменения
+	Path string
+	// fileSettings — настройки в том виде, в каком они лежат в файле, без переопределений
+	fileSettings Settings
+	// overridden — откуда взялись переопределённые настройки, например "flag -tile-size"
+	overridden map[string]string
+
+	Logger *slog.Logger
+}
+
+// LoadConfig читает настройки из файла path (пустой путь — SNAKE_CONFIG или файл по умолчанию)
+// и переопределяет их переменными окружения SNAKE_*. Отсутствующий или пустой файл не ошибка:
+// тогда берутся настройки по умолчанию.
+func LoadConfig(path string) (*Config, error) {
+	if path == "" {
+		path = os.Getenv("SNAKE_CONFIG")
+	}
+	if path == "" {
+		path = DefaultPath()
+	}
+
+	settings := DefaultSettings()
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	// пустой файл — то же, что его отсутствие; поля, которых нет в файле, сохраняют значения по умолчанию
+	if err == nil && len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	cfg := &Config{
+		Settings:             settings.Clone(),
+		TopBarHeight:         30,
+		DatabaseURL:          os.Getenv("DATABASE_URL"),
+		RecordsFile:          os.Getenv("RECORDS_FILE"),
+		Storage:              os.Getenv("STORAGE"),
+		CampaignProgressFile: os.Getenv("CAMPAIGN_PROGRESS_FILE"),
+		SkinsDir:             os.Getenv("SKINS_DIR"),
+		Audio:                os.Getenv("AUDIO"),
+		Path:                 path,
+		fileSettings:         settings,
+		overridden:           make(map[string]string),
+	}
+
+	for _, st := range settingsTable() {
+		value, ok := os.LookupEnv(st.envName())
+		if !ok {
+			continue
+		}
+		if err := st.set(&cfg.Settings, value); err != nil {
+			return nil, fmt.Errorf("environment variable %s: %w", st.envName(), err)
+		}
+		cfg.overridden[st.key] = "env " + st.envName()
+	}
+	if err := cfg.Settings.Validate(); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	// без явного выбора хранилища используем Postgres, если задан DATABASE_URL, иначе файл
+	if cfg.Storage == "" {
+		if cfg.DatabaseURL != "" {
+			cfg.Storage = StoragePostgres
+		} else {
+			cfg.Storage = StorageFile
+		}
+	}
+	if cfg.RecordsFile == "" {
+		cfg.RecordsFile = filepath.Join(dataDir(), "records.json")
+	}
+	if cfg.CampaignProgressFile == "" {
+		cfg.CampaignProgressFile = filepath.Join(dataDir(), "campaign_progress.json")
+	}
+	if cfg.SkinsDir == "" {
+		cfg.SkinsDir = filepath.Join(dataDir(), "skins")
+	}
+	if cfg.Audio == "" {
+		cfg.Audio = AudioEbiten
+	}
+	return cfg, nil
+}
+
+// DefaultPath — файл конфигурации по умолчанию, например ~/.config/snake-game/config.json.
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "config.json"
+	}
+	return filepath.Join(dir, "snake-game", "config.json")
+}
+
+// RegisterFlags добавляет в fs флаги для каждой настройки, например -tile-size.
+// Заданные флаги применяет ApplyFlags.
+func RegisterFlags(fs *flag.FlagSet) {
+	for _, st := range settingsTable() {
+		fs.String(st.flagName(), "", st.usage+" (overrides config file)")
+	}
+}
+
+// ApplyFlags переопределяет настройки флагами, которые были заданы в командной строке.
+func (config *Config) ApplyFlags(fs *flag.FlagSet) error {
+	byFlag := make(map[string]setting)
+	for _, st := range settingsTable() {
+		byFlag[st.flagName()] = st
+	}
+
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		st, ok := byFlag[f.Name]
+		if !ok || err != nil {
+			return
+		}
+		if setErr := st.set(&config.Settings, f.Value.String()); setErr != nil {
+			err = fmt.Errorf("flag -%s: %w", f.Name, setErr)
+			return
+		}
+		config.overridden[st.key] = "flag -" + f.Name
+	})
+	if err != nil {
+		return err
+	}
+	return config.Settings.Validate()
+}
+
+// Overridden возвращает, чем переопределена настройка key, или пустую строку,
+// если её значение взято из файла.
+func (config *Config) Overridden(key string) string {
+	return config.overridden[key]
+}
+
+// Saved возвращает настройки, с которыми игра запустится в следующий раз с теми же флагами:
+// действующие настройки, в которых требующие перезапуска взяты из файла.
+func (config *Config) Saved() Settings {
+	saved := config.Settings.Clone()
+	for _, st := range settingsTable() {
+		if st.restart && config.overridden[st.key] == "" {
+			st.copy(&saved, &config.fileSettings)
+		}
+	}
+	return saved
+}
+
+// Defaults возвращает настройки по умолчанию; переопределённые флагами и переменными
+// окружения остаются такими, как сейчас.
+func (config *Config) Defaults() Settings {
+	defaults := DefaultSettings()
+	for _, st := range settingsTable() {
+		if config.overridden[st.key] != "" {
+			st.copy(&defaults, &config.Settings)
+		}
+	}
+	return defaults
+}
+
+// RequiresRestart сообщает, что настройку key запущенная игра применит только после перезапуска.
+func RequiresRestart(key string) bool {
+	for _, st := range settingsTable() {
+		if st.key == key {
+			return st.restart
+		}
+	}
+	return false
+}
+
+// SaveSettings проверяет настройки, записывает их в файл конфигурации и применяет те,
+// что действуют на лету. restart сообщает, что часть изменений вступит в силу после перезапуска.
+// Переопределённые настройки в файле остаются прежними: флаг действует только на один запуск.
+func (config *Config) SaveSettings(settings Settings) (restart bool, err error) {
+	if err := settings.Validate(); err != nil {
+		return false, err
+	}
+
+	file := settings.Clone()
+	applied := settings.Clone()
+	for _, st := range settingsTable() {
+		if config.overridden[st.key] != "" {
+			st.copy(&file, &config.fileSettings)
+		}
+		if st.restart {
+			if !st.equal(&applied, &config.Settings) {
+				restart = true
+			}
+			st.copy(&applied, &config.Settings)
+		}
+	}
+
+	// без переопределений файл должен оставаться исправным, иначе следующий запуск не состоится
+	if err := file.Validate(); err != nil {
+		return false, fmt.Errorf("settings conflict with values overridden by flags or environment: %w", err)
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("failed to encode settings: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(config.Path), 0755); err != nil {
+		return false, fmt.Errorf("failed to create config directory: %w", err)
+	}
+	tmpPath := config.Path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return false, fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Rename(tmpPath, config.Path); err != nil {
+		return false, fmt.Errorf("failed to replace config file: %w", err)
+	}
+
+	config.fileSettings = file
+	config.Settings = applied
+	return restart, nil
+}
+
+// dataDir возвращает каталог пользовательских данных игры (по умолчанию ~/.local/share/snake-game).
+func dataDir() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "snake-game")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "."
 	}
+	return filepath.Join(home, ".local", "share", "snake-game")
 }
 
 func (config *Config) SetLogger(logger *slog.Logger) {
@@ -0,0 +1,433 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"snake-game/internal/core"
+	"strconv"
+	"strings"
+)
+
+// Действия, которым назначаются клавиши и кнопки геймпада
+const (
+	ActionP1Up    = "p1_up"
+	ActionP1Down  = "p1_down"
+	ActionP1Left  = "p1_left"
+	ActionP1Right = "p1_right"
+	ActionP2Up    = "p2_up"
+	ActionP2Down  = "p2_down"
+	ActionP2Left  = "p2_left"
+	ActionP2Right = "p2_right"
+
+	ActionPause   = "pause"
+	ActionRestart = "restart"
+	ActionConfirm = "confirm"
+	ActionBack    = "back"
+	// ActionFinish заканчивает игру по желанию игрока, например в режиме zen
+	ActionFinish = "finish"
+	// ActionLineup переключает в главном меню состав игроков
+	ActionLineup = "lineup"
+)
+
+// Кнопки стандартного геймпада в раскладке Xbox
+const (
+	ButtonDpadUp     = "DpadUp"
+	ButtonDpadDown   = "DpadDown"
+	ButtonDpadLeft   = "DpadLeft"
+	ButtonDpadRight  = "DpadRight"
+	ButtonA          = "A"
+	ButtonB          = "B"
+	ButtonX          = "X"
+	ButtonY          = "Y"
+	ButtonLB         = "LB"
+	ButtonRB         = "RB"
+	ButtonLT         = "LT"
+	ButtonRT         = "RT"
+	ButtonBack       = "Back"
+	ButtonStart      = "Start"
+	ButtonLeftStick  = "LeftStick"
+	ButtonRightStick = "RightStick"
+	ButtonGuide      = "Guide"
+)
+
+// Границы допустимых значений настроек
+const (
+	MinScreenWidth  = 640
+	MinScreenHeight = 360
+	MinTileSize     = 16
+	MaxTileSize     = 256
+	// MaxSnakeLength — предел начальной длины; на тесном уровне змея всё равно
+	// укорачивается до места за стартом
+	MaxSnakeLength = 20
+)
+
+// Settings — настройки, которые игрок может поменять. Они хранятся в файле конфигурации,
+// а переменные окружения и флаги командной строки переопределяют их на один запуск.
+type Settings struct {
+	ScreenWidth           int `json:"screen_width"`
+	ScreenHeight          int `json:"screen_height"`
+	TileSize              int `json:"tile_size"`
+	InitialSnakeLen       int `json:"initial_snake_len"`
+	InitialSpeed          int `json:"initial_speed"`
+	SpeedIncreaseInterval int `json:"speed_increase_interval"`
+	SpeedIncreaseAmount   int `json:"speed_increase_amount"`
+	MaxSpeed              int `json:"max_speed"`
+
+	Skin string `json:"skin"`
+	// Volume — громкость от 0 до 1
+	Volume float64 `json:"volume"`
+	Muted  bool    `json:"muted"`
+	// KeyBindings — имя клавиши Ebitengine (например "ArrowUp" или "W") для каждого действия
+	KeyBindings map[string]string `json:"key_bindings"`
+	// GamepadBindings — кнопка геймпада для действия; пустая строка — кнопки нет
+	GamepadBindings map[string]string `json:"gamepad_bindings"`
+}
+
+func DefaultSettings() Settings {
+	return Settings{
+		ScreenWidth:           2400,
+		ScreenHeight:          1200,
+		TileSize:              120,
+		InitialSnakeLen:       2,
+		InitialSpeed:          30,
+		SpeedIncreaseInterval: 5,
+		SpeedIncreaseAmount:   5,
+		MaxSpeed:              5,
+		Skin:                  "snake",
+		Volume:                0.8,
+		KeyBindings:           DefaultKeyBindings(),
+		GamepadBindings:       DefaultGamepadBindings(),
+	}
+}
+
+// Actions — все действия в порядке показа на экране настроек.
+func Actions() []string {
+	return append(append(PlayerActions(0), PlayerActions(1)...), CommonActions()...)
+}
+
+// PlayerActions — повороты игрока player (0 или 1) вверх, вниз, влево и вправо.
+func PlayerActions(player int) []string {
+	if player == 0 {
+		return []string{ActionP1Up, ActionP1Down, ActionP1Left, ActionP1Right}
+	}
+	return []string{ActionP2Up, ActionP2Down, ActionP2Left, ActionP2Right}
+}
+
+// CommonActions — действия, общие для всех игроков и меню.
+func CommonActions() []string {
+	return []string{ActionPause, ActionRestart, ActionConfirm, ActionBack, ActionFinish, ActionLineup}
+}
+
+func DefaultKeyBindings() map[string]string {
+	bindings := map[string]string{
+		ActionPause:   "P",
+		ActionRestart: "R",
+		ActionConfirm: "Enter",
+		ActionBack:    "Escape",
+		ActionFinish:  "Q",
+		ActionLineup:  "Tab",
+	}
+	maps.Copy(bindings, keyPresets[0].bindings)
+	return bindings
+}
+
+// GamepadButtons — имена кнопок, которые можно назначить действиям.
+func GamepadButtons() []string {
+	return []string{
+		ButtonDpadUp, ButtonDpadDown, ButtonDpadLeft, ButtonDpadRight,
+		ButtonA, ButtonB, ButtonX, ButtonY,
+		ButtonLB, ButtonRB, ButtonLT, ButtonRT,
+		ButtonBack, ButtonStart, ButtonLeftStick, ButtonRightStick, ButtonGuide,
+	}
+}
+
+// DefaultGamepadBindings — крестовина поворачивает, Start ставит паузу, A подтверждает, B возвращает.
+// Каждый игрок играет своим геймпадом, поэтому у игроков одинаковые кнопки.
+func DefaultGamepadBindings() map[string]string {
+	return map[string]string{
+		ActionP1Up:    ButtonDpadUp,
+		ActionP1Down:  ButtonDpadDown,
+		ActionP1Left:  ButtonDpadLeft,
+		ActionP1Right: ButtonDpadRight,
+		ActionP2Up:    ButtonDpadUp,
+		ActionP2Down:  ButtonDpadDown,
+		ActionP2Left:  ButtonDpadLeft,
+		ActionP2Right: ButtonDpadRight,
+		ActionPause:   ButtonStart,
+		ActionRestart: ButtonBack,
+		ActionConfirm: ButtonA,
+		ActionBack:    ButtonB,
+		ActionFinish:  ButtonY,
+		ActionLineup:  ButtonX,
+	}
+}
+
+// keyPreset — готовая раскладка клавиш поворота для обоих игроков.
+type keyPreset struct {
+	name     string
+	bindings map[string]string
+}
+
+var keyPresets = []keyPreset{
+	{"arrows", map[string]string{
+		ActionP1Up: "ArrowUp", ActionP1Down: "ArrowDown", ActionP1Left: "ArrowLeft", ActionP1Right: "ArrowRight",
+		ActionP2Up: "W", ActionP2Down: "S", ActionP2Left: "A", ActionP2Right: "D",
+	}},
+	{"wasd", map[string]string{
+		ActionP1Up: "W", ActionP1Down: "S", ActionP1Left: "A", ActionP1Right: "D",
+		ActionP2Up: "ArrowUp", ActionP2Down: "ArrowDown", ActionP2Left: "ArrowLeft", ActionP2Right: "ArrowRight",
+	}},
+	{"vim", map[string]string{
+		ActionP1Up: "K", ActionP1Down: "J", ActionP1Left: "H", ActionP1Right: "L",
+		ActionP2Up: "ArrowUp", ActionP2Down: "ArrowDown", ActionP2Left: "ArrowLeft", ActionP2Right: "ArrowRight",
+	}},
+}
+
+// KeyPresets — имена готовых раскладок: стрелки, WASD и HJKL как в vim у первого игрока.
+func KeyPresets() []string {
+	names := make([]string, len(keyPresets))
+	for i, preset := range keyPresets {
+		names[i] = preset.name
+	}
+	return names
+}
+
+// ApplyKeyPreset назначает клавиши поворота из раскладки name; остальные действия не меняются.
+func (s *Settings) ApplyKeyPreset(name string) error {
+	for _, preset := range keyPresets {
+		if preset.name == name {
+			maps.Copy(s.KeyBindings, preset.bindings)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown key preset %q: expected one of %s", name, strings.Join(KeyPresets(), ", "))
+}
+
+// KeyPreset возвращает раскладку, с которой совпадают клавиши поворота, или пустую строку.
+func (s Settings) KeyPreset() string {
+	for _, preset := range keyPresets {
+		matches := true
+		for action, key := range preset.bindings {
+			if !strings.EqualFold(s.KeyBindings[action], key) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return preset.name
+		}
+	}
+	return ""
+}
+
+// Clone возвращает копию настроек, которую можно менять, не трогая оригинал.
+func (s Settings) Clone() Settings {
+	clone := s
+	clone.KeyBindings = maps.Clone(s.KeyBindings)
+	clone.GamepadBindings = maps.Clone(s.GamepadBindings)
+	if clone.KeyBindings == nil {
+		clone.KeyBindings = make(map[string]string)
+	}
+	if clone.GamepadBindings == nil {
+		clone.GamepadBindings = make(map[string]string)
+	}
+	return clone
+}
+
+// Validate проверяет каждое значение и их согласованность между собой.
+// Имена клавиш здесь не разбираются: это делает игра, которая знает раскладку Ebitengine.
+func (s Settings) Validate() error {
+	problems := make([]string, 0)
+	if s.ScreenWidth < MinScreenWidth || s.ScreenHeight < MinScreenHeight {
+		problems = append(problems, fmt.Sprintf("screen %dx%d is smaller than %dx%d", s.ScreenWidth, s.ScreenHeight, MinScreenWidth, MinScreenHeight))
+	}
+	// размер клетки задаёт только фоновую партию меню, поле уровня вписывается в окно само,
+	// поэтому с размером окна он не сверяется
+	if s.TileSize < MinTileSize || s.TileSize > MaxTileSize {
+		problems = append(problems, fmt.Sprintf("tile size %d must be between %d and %d", s.TileSize, MinTileSize, MaxTileSize))
+	}
+	if s.InitialSnakeLen < core.MinSnakeLength || s.InitialSnakeLen > MaxSnakeLength {
+		problems = append(problems, fmt.Sprintf("initial snake length %d must be between %d and %d", s.InitialSnakeLen, core.MinSnakeLength, MaxSnakeLength))
+	}
+	if s.InitialSpeed < 1 || s.MaxSpeed < 1 {
+		problems = append(problems, fmt.Sprintf("initial speed %d and max speed %d must be positive", s.InitialSpeed, s.MaxSpeed))
+	} else if s.MaxSpeed > s.InitialSpeed {
+		// скорость — число тиков между шагами: максимальная скорость — наименьший интервал
+		problems = append(problems, fmt.Sprintf("max speed %d is slower than initial speed %d: speeds are ticks per move, lower is faster", s.MaxSpeed, s.InitialSpeed))
+	}
+	if s.SpeedIncreaseInterval < 1 {
+		problems = append(problems, fmt.Sprintf("speed increase interval %d must be positive", s.SpeedIncreaseInterval))
+	}
+	if s.SpeedIncreaseAmount < 0 {
+		problems = append(problems, fmt.Sprintf("speed increase amount %d must not be negative", s.SpeedIncreaseAmount))
+	}
+	if strings.TrimSpace(s.Skin) == "" {
+		problems = append(problems, "skin is empty")
+	}
+	if s.Volume < 0 || s.Volume > 1 {
+		problems = append(problems, fmt.Sprintf("volume %.2f must be between 0 and 1", s.Volume))
+	}
+	problems = append(problems, s.keyBindingProblems()...)
+	problems = append(problems, s.gamepadBindingProblems()...)
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid settings: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func (s Settings) keyBindingProblems() []string {
+	problems := make([]string, 0)
+	actions := Actions()
+	owners := make(map[string]string)
+	for _, action := range actions {
+		key, ok := s.KeyBindings[action]
+		if !ok || strings.TrimSpace(key) == "" {
+			problems = append(problems, fmt.Sprintf("no key bound to %s", action))
+			continue
+		}
+		normalized := strings.ToLower(key)
+		if owner, taken := owners[normalized]; taken {
+			problems = append(problems, fmt.Sprintf("key %s is bound to both %s and %s", key, owner, action))
+			continue
+		}
+		owners[normalized] = action
+	}
+	for action := range s.KeyBindings {
+		if !slices.Contains(actions, action) {
+			problems = append(problems, fmt.Sprintf("unknown action %q in key bindings", action))
+		}
+	}
+	return problems
+}
+
+// gamepadBindingProblems проверяет кнопки геймпада. Одна кнопка может поворачивать обоих игроков,
+// потому что у каждого свой геймпад, но не может делать у одного игрока два разных дела.
+func (s Settings) gamepadBindingProblems() []string {
+	problems := make([]string, 0)
+	actions := Actions()
+	buttons := GamepadButtons()
+	for action, button := range s.GamepadBindings {
+		if !slices.Contains(actions, action) {
+			problems = append(problems, fmt.Sprintf("unknown action %q in gamepad bindings", action))
+		} else if button != "" && !slices.Contains(buttons, button) {
+			problems = append(problems, fmt.Sprintf("unknown gamepad button %q for %s, expected one of %s", button, action, strings.Join(buttons, ", ")))
+		}
+	}
+
+	reported := make(map[string]bool)
+	for player := range 2 {
+		owners := make(map[string]string)
+		for _, action := range append(PlayerActions(player), CommonActions()...) {
+			button := s.GamepadBindings[action]
+			if button == "" {
+				continue
+			}
+			owner, taken := owners[button]
+			if !taken {
+				owners[button] = action
+				continue
+			}
+			// общие действия конфликтуют у обоих игроков, о них достаточно сказать один раз
+			problem := fmt.Sprintf("gamepad button %s is bound to both %s and %s", button, owner, action)
+			if !reported[problem] {
+				reported[problem] = true
+				problems = append(problems, problem)
+			}
+		}
+	}
+	return problems
+}
+
+// setting описывает настройку, которую можно переопределить переменной окружения или флагом.
+type setting struct {
+	key   string
+	usage string
+	// restart — запущенная игра не может применить настройку на лету, она действует после перезапуска
+	restart bool
+	// field возвращает указатель на поле настроек: *int, *float64, *bool или *string
+	field func(s *Settings) any
+}
+
+func settingsTable() []setting {
+	return []setting{
+		{"screen_width", "initial window width in pixels", true, func(s *Settings) any { return &s.ScreenWidth }},
+		{"screen_height", "initial game field height in pixels", true, func(s *Settings) any { return &s.ScreenHeight }},
+		{"tile_size", "cell size of the menu background field in pixels", true, func(s *Settings) any { return &s.TileSize }},
+		{"initial_snake_len", "snake length at start, from 2 to 20", false, func(s *Settings) any { return &s.InitialSnakeLen }},
+		{"initial_speed", "ticks per move at start (lower is faster)", false, func(s *Settings) any { return &s.InitialSpeed }},
+		{"speed_increase_interval", "points between speed-ups", false, func(s *Settings) any { return &s.SpeedIncreaseInterval }},
+		{"speed_increase_amount", "ticks per move removed by a speed-up", false, func(s *Settings) any { return &s.SpeedIncreaseAmount }},
+		{"max_speed", "smallest ticks per move", false, func(s *Settings) any { return &s.MaxSpeed }},
+		{"skin", "sprite set", false, func(s *Settings) any { return &s.Skin }},
+		{"volume", "sound volume from 0 to 1", false, func(s *Settings) any { return &s.Volume }},
+		{"muted", "turn all sounds off (true or false)", false, func(s *Settings) any { return &s.Muted }},
+	}
+}
+
+// set разбирает value и записывает его в поле настройки.
+func (st setting) set(s *Settings, value string) error {
+	switch field := st.field(s).(type) {
+	case *int:
+		parsed, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: expected integer", st.key, value)
+		}
+		*field = parsed
+	case *float64:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: expected number", st.key, value)
+		}
+		*field = parsed
+	case *bool:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: expected true or false", st.key, value)
+		}
+		*field = parsed
+	case *string:
+		*field = value
+	}
+	return nil
+}
+
+// copy переносит значение настройки из src в dst.
+func (st setting) copy(dst, src *Settings) {
+	switch field := st.field(dst).(type) {
+	case *int:
+		*field = *st.field(src).(*int)
+	case *float64:
+		*field = *st.field(src).(*float64)
+	case *bool:
+		*field = *st.field(src).(*bool)
+	case *string:
+		*field = *st.field(src).(*string)
+	}
+}
+
+// equal сравнивает значение настройки в a и b.
+func (st setting) equal(a, b *Settings) bool {
+	switch field := st.field(a).(type) {
+	case *int:
+		return *field == *st.field(b).(*int)
+	case *float64:
+		return *field == *st.field(b).(*float64)
+	case *bool:
+		return *field == *st.field(b).(*bool)
+	case *string:
+		return *field == *st.field(b).(*string)
+	}
+	return false
+}
+
+// envName — имя переменной окружения для настройки, например SNAKE_TILE_SIZE.
+func (st setting) envName() string {
+	return "SNAKE_" + strings.ToUpper(st.key)
+}
+
+// flagName — имя флага для настройки, например tile-size.
+func (st setting) flagName() string {
+	return strings.ReplaceAll(st.key, "_", "-")
+}
@@ -1,6 +1,10 @@
 package core
 
-import "math"
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
 
 type GameState int
 
@@ -10,12 +14,21 @@ const (
 	GameOverState
 	LevelCreateState
 	BestScoresState
+	ReplayState
+	JoinGameState
+	CampaignState
+	SettingsState
 )
 
 type Position struct {
 	X, Y int
 }
 
+func (p Position) Move(direction Direction) Position {
+	dx, dy := direction.Delta()
+	return Position{X: p.X + dx, Y: p.Y + dy}
+}
+
 type Direction int
 
 const (
@@ -25,6 +38,86 @@ const (
 	Right
 )
 
+// Delta возвращает смещение по осям при шаге в данном направлении.
+func (d Direction) Delta() (dx, dy int) {
+	switch d {
+	case Up:
+		return 0, -1
+	case Down:
+		return 0, 1
+	case Left:
+		return -1, 0
+	case Right:
+		return 1, 0
+	}
+	return 0, 0
+}
+
+func (d Direction) Opposite() Direction {
+	switch d {
+	case Up:
+		return Down
+	case Down:
+		return Up
+	case Left:
+		return Right
+	default:
+		return Left
+	}
+}
+
+func (d Direction) String() string {
+	switch d {
+	case Up:
+		return "up"
+	case Down:
+		return "down"
+	case Left:
+		return "left"
+	case Right:
+		return "right"
+	}
+	return fmt.Sprintf("direction(%d)", int(d))
+}
+
+// ParseDirection разбирает название направления, как его возвращает String.
+func ParseDirection(name string) (Direction, error) {
+	for _, d := range []Direction{Up, Down, Left, Right} {
+		if d.String() == name {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown direction %q", name)
+}
+
+// MarshalJSON записывает направление словом, чтобы файлы уровней было удобно править руками.
+func (d Direction) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON принимает и слово, и число — так направления хранились в старых повторах.
+func (d *Direction) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		var number int
+		if err := json.Unmarshal(data, &number); err != nil {
+			return fmt.Errorf("invalid direction %s", data)
+		}
+		if number < int(Up) || number > int(Right) {
+			return fmt.Errorf("invalid direction %d", number)
+		}
+		*d = Direction(number)
+		return nil
+	}
+
+	parsed, err := ParseDirection(name)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
 func GetDirection(first Position, second Position) Direction {
 	if first.X == second.X {
 		if first.Y < second.Y {
@@ -86,3 +179,11 @@ func CornerToRotationAngle(oldDirection Direction, newDirection Direction) (rota
 	}
 	return
 }
+
+// InterpolateRotationAngle — угол на пути от направления from к направлению to; progress от 0 до 1.
+// Поворачивает кратчайшим путём, так что поворот налево не превращается в три поворота направо.
+func InterpolateRotationAngle(from Direction, to Direction, progress float64) float64 {
+	start := DirectionToRotationAngle(from)
+	delta := math.Remainder(DirectionToRotationAngle(to)-start, 2*math.Pi)
+	return start + delta*progress
+}
@@ -1,19 +1,116 @@
 package core
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Версии формата уровня: в первой были только имя, размер и стены.
+// Файлы без поля version считаются файлами первой версии.
+const (
+	LevelVersion1       = 1
+	CurrentLevelVersion = 2
+)
+
+const (
+	DifficultyEasy   = "easy"
+	DifficultyNormal = "normal"
+	DifficultyHard   = "hard"
+	DifficultyExpert = "expert"
+)
+
 type Level struct {
-	Name string `json:"name"`
+	Version int    `json:"version,omitempty"`
+	Name    string `json:"name"`
+
+	Author      string `json:"author,omitempty"`
+	Description string `json:"description,omitempty"`
+	Difficulty  string `json:"difficulty,omitempty"`
 
 	GridWidth  int `json:"grid_width"`
 	GridHeight int `json:"grid_height"`
 
 	Walls []Wall `json:"walls"`
+
+	// Spawns[i] — стартовая клетка и направление змеи игрока i; без них змеи стартуют в центре
+	Spawns []Spawn `json:"spawns,omitempty"`
+	// InitialLength заменяет начальную длину змеи из настроек, если больше нуля
+	InitialLength int `json:"initial_length,omitempty"`
+	// SpeedCurve заменяет ускорение из настроек: интервал шага в зависимости от счёта
+	SpeedCurve []SpeedPoint `json:"speed_curve,omitempty"`
+	Food       *FoodRules   `json:"food,omitempty"`
+}
+
+type Spawn struct {
+	Position
+	Direction Direction `json:"direction"`
+}
+
+// SpeedPoint — начиная со счёта Score змея делает шаг раз в Interval тиков.
+type SpeedPoint struct {
+	Score    int `json:"score"`
+	Interval int `json:"interval"`
+}
+
+// FoodRules задаёт, где появляется еда. Fixed — клетки, на которых еда появляется
+// по очереди; Zones — прямоугольники, из которых клетка выбирается с учётом веса.
+// Если подходящей свободной клетки нет, еда появляется в любой свободной клетке.
+type FoodRules struct {
+	Fixed []Position `json:"fixed,omitempty"`
+	Zones []FoodZone `json:"zones,omitempty"`
+}
+
+type FoodZone struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// Weight — относительная вероятность зоны; 0 означает 1
+	Weight int `json:"weight,omitempty"`
+}
+
+func (z FoodZone) Contains(pos Position) bool {
+	return pos.X >= z.X && pos.X < z.X+z.Width && pos.Y >= z.Y && pos.Y < z.Y+z.Height
+}
+
+func (z FoodZone) EffectiveWeight() int {
+	return max(z.Weight, 1)
 }
 
 func NewLevel(name string, width, height int, walls []Wall) *Level {
 	return &Level{
+		Version:    CurrentLevelVersion,
 		Name:       name,
 		GridWidth:  width,
 		GridHeight: height,
 		Walls:      walls,
 	}
 }
+
+// ParseLevel разбирает уровень любой поддерживаемой версии.
+func ParseLevel(data []byte) (*Level, error) {
+	var level Level
+	if err := json.Unmarshal(data, &level); err != nil {
+		return nil, err
+	}
+	if level.Version == 0 {
+		level.Version = LevelVersion1
+	}
+	if level.Version > CurrentLevelVersion {
+		return nil, fmt.Errorf("unsupported level version %d: expected at most %d", level.Version, CurrentLevelVersion)
+	}
+	return &level, nil
+}
+
+// MoveInterval возвращает интервал шага по кривой скорости для данного счёта;
+// false — кривая не задана или ещё не началась.
+func (l *Level) MoveInterval(score int) (int, bool) {
+	interval, found := 0, false
+	bestScore := -1
+	for _, point := range l.SpeedCurve {
+		if point.Score <= score && point.Score > bestScore {
+			interval, found, bestScore = point.Interval, true, point.Score
+		}
+	}
+	return interval, found
+}
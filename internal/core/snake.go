@@ -4,6 +4,9 @@ import (
 	"fmt"
 )
 
+// MinSnakeLength — наименьшая длина змеи: у неё всегда есть голова и хвост.
+const MinSnakeLength = 2
+
 type SnakeSegment struct {
 	Position
 }
@@ -13,35 +16,47 @@ func NewSnakeSegment(x, y int) *SnakeSegment {
 }
 
 type Snake struct {
-	Body          []SnakeSegment
-	Direction     Direction
+	Body      []SnakeSegment
+	Direction Direction
+	// NextDirection — куда змея шагнёт на следующем шаге: первый поворот из очереди или Direction
 	NextDirection Direction
 
 	IsAlive bool
 
+	// turns — повороты, которые змея выполнит по одному на шаг; не длиннее turnQueueSize.
+	// При turnQueueSize 0 очереди нет: новый поворот заменяет NextDirection, как в старых повторах
+	turns         []Direction
+	turnQueueSize int
+
+	// previousTail — где был хвост до последнего шага; nil, пока змея не шагала.
+	// По нему и по moveTimer змею рисуют плавно, хотя ходит она по клеткам
+	previousTail *Position
+
 	minMoveInterval int
 	moveInterval    int
 	moveTimer       int
 }
 
-func NewSnake(x, y, snakeLength, moveInterval, minMoveInterval int) (*Snake, error) {
-	if snakeLength < 2 {
-		return nil, fmt.Errorf("invalid snake size: expected greater than 1, received %d", snakeLength)
+func NewSnake(x, y int, direction Direction, snakeLength, moveInterval, minMoveInterval int) (*Snake, error) {
+	if snakeLength < MinSnakeLength {
+		return nil, fmt.Errorf("invalid snake size: expected at least %d, received %d", MinSnakeLength, snakeLength)
 	}
 
 	if moveInterval <= 0 {
 		return nil, fmt.Errorf("invalid extendForward interval: expected positive value, received %d", moveInterval)
 	}
 
+	// тело растёт в сторону, противоположную направлению движения
+	dx, dy := direction.Delta()
 	body := make([]SnakeSegment, snakeLength)
 	for i := 0; i < snakeLength; i++ {
-		body[i] = *NewSnakeSegment(x-i, y)
+		body[i] = *NewSnakeSegment(x-i*dx, y-i*dy)
 	}
 
 	snake := Snake{
 		Body:            body,
-		Direction:       Right,
-		NextDirection:   Right,
+		Direction:       direction,
+		NextDirection:   direction,
 		IsAlive:         true,
 		moveInterval:    moveInterval,
 		minMoveInterval: minMoveInterval,
@@ -51,30 +66,48 @@ func NewSnake(x, y, snakeLength, moveInterval, minMoveInterval int) (*Snake, err
 	return &snake, nil
 }
 
-func (s *Snake) SetNextDirection(direction Direction) {
-	var isOpposite = false
+// SetTurnQueueSize задаёт, сколько поворотов змея запоминает вперёд.
+func (s *Snake) SetTurnQueueSize(size int) {
+	s.turnQueueSize = max(size, 0)
+	if len(s.turns) > s.turnQueueSize {
+		s.turns = s.turns[:s.turnQueueSize]
+	}
+}
 
-	switch direction {
-	case Up:
-		if s.Direction == Down {
-			isOpposite = true
-		}
-	case Down:
-		if s.Direction == Up {
-			isOpposite = true
-		}
-	case Left:
-		if s.Direction == Right {
-			isOpposite = true
-		}
-	case Right:
-		if s.Direction == Left {
-			isOpposite = true
+// SetNextDirection ставит поворот в очередь. Поворот проверяется по направлению, которое у змеи
+// будет после уже стоящих в очереди поворотов: разворот назад и повтор того же направления
+// отбрасываются, как и повороты сверх размера очереди. Возвращает, принят ли поворот.
+func (s *Snake) SetNextDirection(direction Direction) bool {
+	if s.turnQueueSize == 0 {
+		// без очереди поворот сверяется с текущим направлением и заменяет предыдущий
+		if direction == s.Direction.Opposite() {
+			return false
 		}
-	}
-	if !isOpposite {
 		s.NextDirection = direction
+		return true
+	}
+
+	last := s.PlannedDirection()
+	if direction == last || direction == last.Opposite() || len(s.turns) >= s.turnQueueSize {
+		return false
+	}
+	s.turns = append(s.turns, direction)
+	s.NextDirection = s.turns[0]
+	return true
+}
+
+// PlannedDirection — направление змеи после всех поворотов из очереди.
+func (s *Snake) PlannedDirection() Direction {
+	if len(s.turns) > 0 {
+		return s.turns[len(s.turns)-1]
 	}
+	return s.NextDirection
+}
+
+// ClearTurns забывает повороты из очереди, например когда состояние змеи пришло по сети.
+func (s *Snake) ClearTurns() {
+	s.turns = s.turns[:0]
+	s.NextDirection = s.Direction
 }
 
 func (s *Snake) Update() bool {
@@ -87,20 +120,33 @@ func (s *Snake) Update() bool {
 	return true
 }
 
+// MoveProgress — какую часть пути до следующего шага змея прошла: 0 сразу после шага,
+// почти 1 перед следующим.
+func (s *Snake) MoveProgress() float64 {
+	return min(float64(s.moveTimer)/float64(s.moveInterval), 1)
+}
+
+// PreviousTail возвращает клетку хвоста до последнего шага. Если змея на нём выросла,
+// это её нынешний хвост; false — змея ещё не шагала.
+func (s *Snake) PreviousTail() (Position, bool) {
+	if s.previousTail == nil {
+		return Position{}, false
+	}
+	return *s.previousTail, true
+}
+
 func (s *Snake) extendForward() {
+	tail := s.Body[len(s.Body)-1].Position
+	s.previousTail = &tail
 	s.Direction = s.NextDirection
-	oldHead := s.Body[0]
-	newHeadPos := oldHead.Position
-	switch s.Direction {
-	case Up:
-		newHeadPos.Y--
-	case Left:
-		newHeadPos.X--
-	case Down:
-		newHeadPos.Y++
-	case Right:
-		newHeadPos.X++
+	if len(s.turns) > 0 {
+		s.turns = s.turns[1:]
 	}
+	if len(s.turns) > 0 {
+		s.NextDirection = s.turns[0]
+	}
+	oldHead := s.Body[0]
+	newHeadPos := oldHead.Position.Move(s.Direction)
 	newHead := SnakeSegment{newHeadPos}
 	s.Body = append([]SnakeSegment{newHead}, s.Body...)
 }
@@ -117,11 +163,22 @@ func (s *Snake) DecreaseMoveInterval(x int) {
 	s.moveInterval = max(s.moveInterval-x, s.minMoveInterval)
 }
 
+// SetMoveInterval задаёт интервал шага напрямую, например по кривой скорости уровня.
+func (s *Snake) SetMoveInterval(x int) {
+	s.moveInterval = max(x, 1)
+}
+
 func (s *Snake) CheckCollisionsWithSelf() {
+	if s.HitsItself() {
+		s.IsAlive = false
+	}
+}
+
+func (s *Snake) HitsItself() bool {
 	for i := 1; i < len(s.Body); i++ {
 		if s.Body[i].Position == s.Body[0].Position {
-			s.IsAlive = false
-			break
+			return true
 		}
 	}
+	return false
 }
@@ -0,0 +1,237 @@
+package editor
+
+import (
+	"snake-game/internal/core"
+	"sort"
+)
+
+// Symmetry — как зеркально повторяются правки на поле.
+type Symmetry int
+
+const (
+	NoSymmetry Symmetry = iota
+	// MirrorX отражает клетку относительно вертикальной оси поля
+	MirrorX
+	// MirrorY отражает клетку относительно горизонтальной оси поля
+	MirrorY
+	// MirrorXY отражает клетку относительно обеих осей
+	MirrorXY
+)
+
+func (s Symmetry) String() string {
+	switch s {
+	case MirrorX:
+		return "X"
+	case MirrorY:
+		return "Y"
+	case MirrorXY:
+		return "XY"
+	}
+	return "off"
+}
+
+func (s Symmetry) Next() Symmetry {
+	return (s + 1) % (MirrorXY + 1)
+}
+
+// Canvas — редактируемые стены уровня. Правки объединяются в штрихи:
+// всё, что изменилось между BeginStroke и EndStroke, отменяется одним Undo.
+type Canvas struct {
+	walls  map[core.Position]bool
+	width  int
+	height int
+
+	Symmetry Symmetry
+
+	history History
+	stroke  *Change
+}
+
+func NewCanvas(width, height int) *Canvas {
+	return &Canvas{
+		walls:  make(map[core.Position]bool),
+		width:  width,
+		height: height,
+	}
+}
+
+// Load заменяет поле стенами открытого уровня и очищает историю правок.
+func (c *Canvas) Load(width, height int, walls []core.Wall) {
+	c.walls = make(map[core.Position]bool, len(walls))
+	c.width, c.height = width, height
+	c.history = History{}
+	c.stroke = nil
+	for _, wall := range walls {
+		c.walls[wall.Position] = true
+	}
+}
+
+// Resize меняет размер поля. Стены за его пределами не удаляются:
+// они снова появятся, если поле увеличить обратно.
+func (c *Canvas) Resize(width, height int) {
+	c.width, c.height = width, height
+}
+
+func (c *Canvas) Width() int {
+	return c.width
+}
+
+func (c *Canvas) Height() int {
+	return c.height
+}
+
+func (c *Canvas) IsInside(pos core.Position) bool {
+	return pos.X >= 0 && pos.X < c.width && pos.Y >= 0 && pos.Y < c.height
+}
+
+// IsWall — есть ли стена в клетке внутри поля.
+func (c *Canvas) IsWall(pos core.Position) bool {
+	return c.IsInside(pos) && c.walls[pos]
+}
+
+// Walls возвращает стены внутри текущего поля построчно, чтобы файл уровня
+// не менялся от сохранения к сохранению без правок.
+func (c *Canvas) Walls() []core.Wall {
+	walls := make([]core.Wall, 0, len(c.walls))
+	for pos := range c.walls {
+		if c.IsInside(pos) {
+			walls = append(walls, *core.NewWall(pos.X, pos.Y))
+		}
+	}
+	sort.Slice(walls, func(i, j int) bool {
+		if walls[i].Y != walls[j].Y {
+			return walls[i].Y < walls[j].Y
+		}
+		return walls[i].X < walls[j].X
+	})
+	return walls
+}
+
+// BeginStroke начинает штрих; правки без начатого штриха записываются каждая отдельно.
+func (c *Canvas) BeginStroke() {
+	c.EndStroke()
+	c.stroke = &Change{}
+}
+
+// EndStroke завершает штрих и кладёт его в историю, если он что-то изменил.
+func (c *Canvas) EndStroke() {
+	if c.stroke == nil {
+		return
+	}
+	c.history.Push(*c.stroke)
+	c.stroke = nil
+}
+
+func (c *Canvas) IsStroking() bool {
+	return c.stroke != nil
+}
+
+// Set ставит (wall = true) или стирает стену в клетке и в её зеркальных отражениях.
+func (c *Canvas) Set(pos core.Position, wall bool) {
+	single := c.stroke == nil
+	if single {
+		c.stroke = &Change{}
+	}
+	for _, cell := range c.Mirrored(pos) {
+		c.set(cell, wall)
+	}
+	if single {
+		c.EndStroke()
+	}
+}
+
+// SetAll — Set для каждой клетки из cells.
+func (c *Canvas) SetAll(cells []core.Position, wall bool) {
+	single := c.stroke == nil
+	if single {
+		c.BeginStroke()
+	}
+	for _, pos := range cells {
+		c.Set(pos, wall)
+	}
+	if single {
+		c.EndStroke()
+	}
+}
+
+// Replace заменяет все стены поля на walls одной отменяемой правкой, без учёта симметрии.
+func (c *Canvas) Replace(walls []core.Wall) {
+	c.BeginStroke()
+	target := make(map[core.Position]bool, len(walls))
+	for _, wall := range walls {
+		target[wall.Position] = true
+	}
+	for pos := range c.walls {
+		if !target[pos] {
+			c.set(pos, false)
+		}
+	}
+	for pos := range target {
+		c.set(pos, true)
+	}
+	c.EndStroke()
+}
+
+func (c *Canvas) set(pos core.Position, wall bool) {
+	if !c.IsInside(pos) || c.walls[pos] == wall {
+		return
+	}
+	c.stroke.add(pos, c.walls[pos], wall)
+	c.apply(pos, wall)
+}
+
+func (c *Canvas) apply(pos core.Position, wall bool) {
+	if wall {
+		c.walls[pos] = true
+	} else {
+		delete(c.walls, pos)
+	}
+}
+
+// Mirrored возвращает клетку и её отражения по текущей симметрии.
+func (c *Canvas) Mirrored(pos core.Position) []core.Position {
+	cells := []core.Position{pos}
+	flipX := core.Position{X: c.width - 1 - pos.X, Y: pos.Y}
+	flipY := core.Position{X: pos.X, Y: c.height - 1 - pos.Y}
+	switch c.Symmetry {
+	case MirrorX:
+		cells = append(cells, flipX)
+	case MirrorY:
+		cells = append(cells, flipY)
+	case MirrorXY:
+		cells = append(cells, flipX, flipY, core.Position{X: flipX.X, Y: flipY.Y})
+	}
+	return cells
+}
+
+func (c *Canvas) Undo() bool {
+	c.EndStroke()
+	change, ok := c.history.undo()
+	if !ok {
+		return false
+	}
+	for i := len(change.cells) - 1; i >= 0; i-- {
+		c.apply(change.cells[i].pos, change.cells[i].was)
+	}
+	return true
+}
+
+func (c *Canvas) Redo() bool {
+	c.EndStroke()
+	change, ok := c.history.redo()
+	if !ok {
+		return false
+	}
+	for _, cell := range change.cells {
+		c.apply(cell.pos, cell.now)
+	}
+	return true
+}
+
+func (c *Canvas) CanUndo() bool {
+	return c.history.canUndo()
+}
+
+func (c *Canvas) CanRedo() bool {
+	return c.history.canRedo()
+}
@@ -0,0 +1,69 @@
+package editor
+
+import "snake-game/internal/core"
+
+// MaxHistory — сколько последних правок можно отменить
+const MaxHistory = 200
+
+type cellChange struct {
+	pos      core.Position
+	was, now bool
+}
+
+// Change — одна отменяемая правка: изменения клеток в порядке их применения.
+type Change struct {
+	cells []cellChange
+}
+
+func (c *Change) add(pos core.Position, was, now bool) {
+	c.cells = append(c.cells, cellChange{pos: pos, was: was, now: now})
+}
+
+func (c *Change) IsEmpty() bool {
+	return len(c.cells) == 0
+}
+
+// History — стеки отмены и повтора; новая правка очищает стек повтора.
+type History struct {
+	undoStack []Change
+	redoStack []Change
+}
+
+func (h *History) Push(change Change) {
+	if change.IsEmpty() {
+		return
+	}
+	h.undoStack = append(h.undoStack, change)
+	if len(h.undoStack) > MaxHistory {
+		h.undoStack = h.undoStack[len(h.undoStack)-MaxHistory:]
+	}
+	h.redoStack = nil
+}
+
+func (h *History) undo() (Change, bool) {
+	if len(h.undoStack) == 0 {
+		return Change{}, false
+	}
+	change := h.undoStack[len(h.undoStack)-1]
+	h.undoStack = h.undoStack[:len(h.undoStack)-1]
+	h.redoStack = append(h.redoStack, change)
+	return change, true
+}
+
+func (h *History) redo() (Change, bool) {
+	if len(h.redoStack) == 0 {
+		return Change{}, false
+	}
+	change := h.redoStack[len(h.redoStack)-1]
+	h.redoStack = h.redoStack[:len(h.redoStack)-1]
+	h.undoStack = append(h.undoStack, change)
+	return change, true
+}
+
+func (h *History) canUndo() bool {
+	return len(h.undoStack) > 0
+}
+
+func (h *History) canRedo() bool {
+	return len(h.redoStack) > 0
+}
@@ -0,0 +1,134 @@
+package editor
+
+import "snake-game/internal/core"
+
+type Tool int
+
+const (
+	// Pen рисует стены под курсором, пока нажата кнопка мыши
+	Pen Tool = iota
+	// Rect рисует контур прямоугольника между начальной и конечной клеткой
+	Rect
+	// Line рисует отрезок между начальной и конечной клеткой
+	Line
+	// Fill заливает связную область клеток того же вида, что и клетка под курсором
+	Fill
+)
+
+func Tools() []Tool {
+	return []Tool{Pen, Rect, Line, Fill}
+}
+
+func (t Tool) String() string {
+	switch t {
+	case Pen:
+		return "Pen"
+	case Rect:
+		return "Rect"
+	case Line:
+		return "Line"
+	case Fill:
+		return "Fill"
+	}
+	return "unknown"
+}
+
+// IsShape — инструмент рисует фигуру по двум клеткам, и до отпускания кнопки она только показывается.
+func (t Tool) IsShape() bool {
+	return t == Rect || t == Line
+}
+
+// Shape возвращает клетки фигуры инструмента от from до to.
+func (t Tool) Shape(from, to core.Position) []core.Position {
+	switch t {
+	case Rect:
+		return RectCells(from, to)
+	case Line, Pen:
+		return LineCells(from, to)
+	}
+	return []core.Position{to}
+}
+
+// LineCells — клетки отрезка от from до to по алгоритму Брезенхэма.
+func LineCells(from, to core.Position) []core.Position {
+	dx, dy := abs(to.X-from.X), -abs(to.Y-from.Y)
+	stepX, stepY := 1, 1
+	if from.X > to.X {
+		stepX = -1
+	}
+	if from.Y > to.Y {
+		stepY = -1
+	}
+
+	cells := make([]core.Position, 0, max(dx, -dy)+1)
+	pos := from
+	errorTerm := dx + dy
+	for {
+		cells = append(cells, pos)
+		if pos == to {
+			return cells
+		}
+		doubled := 2 * errorTerm
+		if doubled >= dy {
+			errorTerm += dy
+			pos.X += stepX
+		}
+		if doubled <= dx {
+			errorTerm += dx
+			pos.Y += stepY
+		}
+	}
+}
+
+// RectCells — клетки контура прямоугольника с противоположными углами from и to.
+func RectCells(from, to core.Position) []core.Position {
+	left, right := min(from.X, to.X), max(from.X, to.X)
+	top, bottom := min(from.Y, to.Y), max(from.Y, to.Y)
+
+	cells := make([]core.Position, 0)
+	for x := left; x <= right; x++ {
+		cells = append(cells, core.Position{X: x, Y: top})
+		if bottom != top {
+			cells = append(cells, core.Position{X: x, Y: bottom})
+		}
+	}
+	for y := top + 1; y < bottom; y++ {
+		cells = append(cells, core.Position{X: left, Y: y})
+		if right != left {
+			cells = append(cells, core.Position{X: right, Y: y})
+		}
+	}
+	return cells
+}
+
+// FillCells возвращает связную область поля вокруг start, клетки которой совпадают со start:
+// все стены или все свободные клетки.
+func (c *Canvas) FillCells(start core.Position) []core.Position {
+	if !c.IsInside(start) {
+		return nil
+	}
+	target := c.IsWall(start)
+	visited := map[core.Position]bool{start: true}
+	queue := []core.Position{start}
+	cells := make([]core.Position, 0)
+	for len(queue) > 0 {
+		pos := queue[0]
+		queue = queue[1:]
+		cells = append(cells, pos)
+		for _, direction := range []core.Direction{core.Up, core.Down, core.Left, core.Right} {
+			next := pos.Move(direction)
+			if c.IsInside(next) && !visited[next] && c.IsWall(next) == target {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return cells
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -5,21 +5,32 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 	"log/slog"
 	"snake-game/internal/assets"
+	"snake-game/internal/audio"
 	"snake-game/internal/config"
 	"snake-game/internal/core"
+	"snake-game/internal/input"
+	"snake-game/internal/replay"
 	"snake-game/internal/scenes"
+	"snake-game/internal/sim"
 	"snake-game/internal/storage"
+	"snake-game/internal/ui"
 	"time"
 )
 
 type Game struct {
 	cfg    *config.Config
 	assets *assets.Assets
+	skins  *assets.Registry
+	audio  audio.Player
+	input  *input.Map
 	logger *slog.Logger
 	repo   storage.Repository
+	screen ui.Screen
 
-	score    int
-	gameTime time.Duration
+	scores     []int
+	gameTime   time.Duration
+	lastReplay *replay.Replay
+	setup      scenes.GameSetup
 
 	scenes       map[core.GameState]scenes.Scene
 	currentScene scenes.Scene
@@ -33,38 +44,79 @@ func (g *Game) Assets() *assets.Assets {
 	return g.assets
 }
 
+func (g *Game) Skins() *assets.Registry {
+	return g.skins
+}
+
+func (g *Game) Audio() audio.Player {
+	return g.audio
+}
+
+func (g *Game) Input() *input.Map {
+	return g.input
+}
+
 func (g *Game) Logger() *slog.Logger {
 	return g.logger
 }
 
+func (g *Game) Screen() ui.Screen {
+	return g.screen
+}
+
 func (g *Game) Repository() storage.Repository {
 	return g.repo
 }
 
-func (g *Game) Score() int {
-	return g.score
+func (g *Game) Score(player int) int {
+	if player < 0 || player >= len(g.scores) {
+		return 0
+	}
+	return g.scores[player]
 }
 
 func (g *Game) GameTime() time.Duration {
 	return g.gameTime
 }
 
-func NewGame(cfg *config.Config, assets *assets.Assets, repo storage.Repository) (*Game, error) {
+func (g *Game) LastReplay() *replay.Replay {
+	return g.lastReplay
+}
+
+// sceneMusic — музыка сцен; сцены, которых здесь нет, играют музыку меню.
+var sceneMusic = map[core.GameState]audio.Track{
+	core.GamePlayingState: audio.TrackGame,
+	core.ReplayState:      audio.TrackGame,
+}
+
+func NewGame(cfg *config.Config, assets *assets.Assets, skins *assets.Registry, player audio.Player, repo storage.Repository) (*Game, error) {
 	g := &Game{
 		cfg:    cfg,
 		assets: assets,
+		skins:  skins,
+		audio:  player,
+		input:  input.NewMap(cfg.Settings, cfg.Logger),
 		logger: cfg.Logger,
 		repo:   repo,
+		// до первого кадра окно такого размера, каким его открывает main
+		screen: ui.NewScreen(cfg.ScreenWidth, cfg.WindowHeight(), cfg.TopBarHeight),
+	}
+	ui.ClickSound = func() {
+		g.audio.Play(audio.SoundClick)
 	}
 
 	mainMenuScene := scenes.NewMainMenuScene(g)
 	createLevelScene := scenes.NewCreateLevelScene(g)
 	rankingScene := scenes.NewRankingScene(g)
+	joinScene := scenes.NewJoinScene(g)
+	campaignScene := scenes.NewCampaignScene(g)
 
 	g.scenes = map[core.GameState]scenes.Scene{
 		core.MainMenuState:    mainMenuScene,
 		core.LevelCreateState: createLevelScene,
 		core.BestScoresState:  rankingScene,
+		core.JoinGameState:    joinScene,
+		core.CampaignState:    campaignScene,
 	}
 
 	g.currentScene = mainMenuScene
@@ -88,37 +140,82 @@ func NewGame(cfg *config.Config, assets *assets.Assets, repo storage.Repository)
 }
 
 func (g *Game) Reset() error {
-	g.score = 0
+	for i := range g.scores {
+		g.scores[i] = 0
+	}
 	g.gameTime = 0
 	return nil
 }
 
-func (g *Game) StartGame(level *core.Level) {
-	g.logger.Info("start game command received", "level_name", level.Name)
+func (g *Game) StartGame(setup scenes.GameSetup) {
+	if setup.Mode == nil {
+		setup.Mode = sim.ClassicMode{}
+	}
+	setup.Players = max(setup.Players, 1)
+	g.logger.Info("start game command received", "level_name", setup.Level.Name, "mode", setup.Mode.Name(), "players", setup.Players)
 
-	playingScene, err := scenes.NewPlayingScene(g, level)
+	playingScene, err := scenes.NewPlayingScene(g, setup)
 	if err != nil {
-		g.logger.Error("failed to start level", "level_name", level.Name)
+		g.logger.Error("failed to start level", "level_name", setup.Level.Name, "error", err)
 		return
 	}
 
+	g.scores = make([]int, setup.Players)
 	err = g.Reset()
 	if err != nil {
 		g.logger.Error("failed to reset level", "error", err)
 		return
 	}
+	g.lastReplay = nil
+	g.setup = setup
 
 	playingScene.OnEnter()
 	g.scenes[core.GamePlayingState] = playingScene
 	g.currentScene = playingScene
 
-	gameOverScene := scenes.NewGameOverScene(g, level)
+	gameOverScene := scenes.NewGameOverScene(g, setup)
 	g.scenes[core.GameOverState] = gameOverScene
 
 	g.logger.Info("switched to playing scene")
 }
 
+func (g *Game) FinishGame(rep *replay.Replay) {
+	g.lastReplay = rep
+	g.logger.Info("game finished", "scores", rep.Scores, "ticks", rep.Ticks)
+	if g.setup.OnFinish != nil {
+		g.setup.OnFinish(rep)
+	}
+}
+
+func (g *Game) WatchReplay(rep *replay.Replay, returnState core.GameState) {
+	g.logger.Info("watch replay command received", "level_name", rep.Level.Name, "seed", rep.Seed)
+
+	replayScene, err := scenes.NewReplayScene(g, rep, returnState)
+	if err != nil {
+		g.logger.Error("failed to start replay", "error", err)
+		return
+	}
+
+	replayScene.OnEnter()
+	g.scenes[core.ReplayState] = replayScene
+	g.currentScene = replayScene
+
+	g.logger.Info("switched to replay scene")
+}
+
+func (g *Game) OpenSettings(returnState core.GameState) {
+	g.logger.Info("open settings command received", "return_state", returnState)
+
+	settingsScene := scenes.NewSettingsScene(g, returnState)
+	settingsScene.OnEnter()
+	g.scenes[core.SettingsState] = settingsScene
+	g.currentScene = settingsScene
+
+	g.logger.Info("switched to settings scene")
+}
+
 func (g *Game) Update() error {
+	g.input.Update()
 	newState, err := g.currentScene.Update()
 	if err != nil {
 		return err
@@ -134,30 +231,47 @@ func (g *Game) Update() error {
 
 		g.currentScene.OnEnter()
 	}
+	g.audio.PlayMusic(musicFor(newState))
 
-	_, ok := g.currentScene.(*scenes.PlayingScene)
-	if ok {
+	// на паузе время игры стоит
+	playingScene, ok := g.currentScene.(*scenes.PlayingScene)
+	if ok && !playingScene.IsPaused() {
 		g.gameTime += time.Second / time.Duration(ebiten.TPS())
 	}
 	return nil
 }
 
+func musicFor(state core.GameState) audio.Track {
+	if track, ok := sceneMusic[state]; ok {
+		return track
+	}
+	return audio.TrackMenu
+}
+
 func (g *Game) Draw(screen *ebiten.Image) {
 	g.currentScene.Draw(screen)
 }
 
-func (g *Game) NotifyFoodEaten() bool {
-	g.score++
-	g.logger.Info("snake ate food", "new_score", g.score)
-
-	if g.cfg.SpeedIncreaseInterval > 0 && g.score%g.cfg.SpeedIncreaseInterval == 0 {
-		g.logger.Info("speed increase condition met")
-		return true
+func (g *Game) NotifyScored(player int) {
+	if player < 0 || player >= len(g.scores) {
+		return
 	}
-
-	return false
+	g.scores[player]++
+	g.logger.Info("player scored", "player", player, "new_score", g.scores[player])
 }
 
+// Layout подбирает логический экран под окно. Когда окно меняет размер, сцены раскладывают
+// кнопки заново, а поле уровня при отрисовке вписывается в новый экран.
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return g.cfg.ScreenWidth, g.cfg.WindowHeight()
+	screen := ui.NewScreen(outsideWidth, outsideHeight, g.cfg.TopBarHeight)
+	if screen != g.screen {
+		g.logger.Debug("screen resized", "width", screen.Width, "height", screen.Height, "window_width", outsideWidth, "window_height", outsideHeight)
+		g.screen = screen
+		for _, scene := range g.scenes {
+			if resizable, ok := scene.(scenes.Resizable); ok {
+				resizable.Relayout()
+			}
+		}
+	}
+	return screen.Width, screen.Height
 }
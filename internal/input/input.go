@@ -0,0 +1,243 @@
+package input
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"log/slog"
+	"slices"
+	"snake-game/internal/config"
+	"snake-game/internal/core"
+)
+
+// stickThreshold — насколько надо отклонить стик, чтобы он считался нажатым в эту сторону
+const stickThreshold = 0.5
+
+// directions — направления в порядке действий из config.PlayerActions.
+var directions = []core.Direction{core.Up, core.Down, core.Left, core.Right}
+
+// menuKeys — стрелки работают в меню всегда, какие бы клавиши ни были назначены игрокам.
+var menuKeys = []ebiten.Key{ebiten.KeyArrowUp, ebiten.KeyArrowDown, ebiten.KeyArrowLeft, ebiten.KeyArrowRight}
+
+var buttons = map[string]ebiten.StandardGamepadButton{
+	config.ButtonDpadUp:     ebiten.StandardGamepadButtonLeftTop,
+	config.ButtonDpadDown:   ebiten.StandardGamepadButtonLeftBottom,
+	config.ButtonDpadLeft:   ebiten.StandardGamepadButtonLeftLeft,
+	config.ButtonDpadRight:  ebiten.StandardGamepadButtonLeftRight,
+	config.ButtonA:          ebiten.StandardGamepadButtonRightBottom,
+	config.ButtonB:          ebiten.StandardGamepadButtonRightRight,
+	config.ButtonX:          ebiten.StandardGamepadButtonRightLeft,
+	config.ButtonY:          ebiten.StandardGamepadButtonRightTop,
+	config.ButtonLB:         ebiten.StandardGamepadButtonFrontTopLeft,
+	config.ButtonRB:         ebiten.StandardGamepadButtonFrontTopRight,
+	config.ButtonLT:         ebiten.StandardGamepadButtonFrontBottomLeft,
+	config.ButtonRT:         ebiten.StandardGamepadButtonFrontBottomRight,
+	config.ButtonBack:       ebiten.StandardGamepadButtonCenterLeft,
+	config.ButtonStart:      ebiten.StandardGamepadButtonCenterRight,
+	config.ButtonLeftStick:  ebiten.StandardGamepadButtonLeftStick,
+	config.ButtonRightStick: ebiten.StandardGamepadButtonRightStick,
+	config.ButtonGuide:      ebiten.StandardGamepadButtonCenterCenter,
+}
+
+// Map сопоставляет действиям из настроек клавиши и кнопки геймпада. Геймпады раздаются
+// игрокам по порядку подключения: первый достаётся первому игроку-человеку.
+// Левый стик геймпада всегда поворачивает змею, как и крестовина по умолчанию.
+type Map struct {
+	keys    map[string]ebiten.Key
+	buttons map[string]ebiten.StandardGamepadButton
+
+	gamepads []ebiten.GamepadID
+	// sticks и previousSticks — куда отклонён левый стик каждого геймпада сейчас и на прошлом кадре
+	sticks         map[ebiten.GamepadID]core.Direction
+	previousSticks map[ebiten.GamepadID]core.Direction
+
+	logger *slog.Logger
+}
+
+func NewMap(settings config.Settings, logger *slog.Logger) *Map {
+	m := &Map{
+		sticks:         make(map[ebiten.GamepadID]core.Direction),
+		previousSticks: make(map[ebiten.GamepadID]core.Direction),
+		logger:         logger,
+	}
+	m.SetBindings(settings)
+	return m
+}
+
+// SetBindings заменяет назначения. Неизвестное имя клавиши заменяется клавишей
+// по умолчанию, чтобы испорченный файл не оставил игрока без управления.
+func (m *Map) SetBindings(settings config.Settings) {
+	defaultKeys := config.DefaultKeyBindings()
+	m.keys = make(map[string]ebiten.Key)
+	m.buttons = make(map[string]ebiten.StandardGamepadButton)
+	for _, action := range config.Actions() {
+		var key ebiten.Key
+		if err := key.UnmarshalText([]byte(settings.KeyBindings[action])); err != nil {
+			m.logger.Warn("invalid key binding, using default", "action", action, "key", settings.KeyBindings[action], "error", err)
+			_ = key.UnmarshalText([]byte(defaultKeys[action]))
+		}
+		m.keys[action] = key
+
+		if button, ok := buttons[settings.GamepadBindings[action]]; ok {
+			m.buttons[action] = button
+		}
+	}
+}
+
+// Update запоминает подключённые геймпады и положение их стиков; вызывается один раз за кадр
+// до того, как сцены спрашивают о нажатиях.
+func (m *Map) Update() {
+	for _, id := range inpututil.AppendJustConnectedGamepadIDs(nil) {
+		m.logger.Info("gamepad connected", "id", id, "name", ebiten.GamepadName(id), "standard", ebiten.IsStandardGamepadLayoutAvailable(id))
+	}
+	m.gamepads = m.gamepads[:0]
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if ebiten.IsStandardGamepadLayoutAvailable(id) {
+			m.gamepads = append(m.gamepads, id)
+		}
+	}
+	slices.Sort(m.gamepads)
+
+	m.previousSticks, m.sticks = m.sticks, m.previousSticks
+	clear(m.sticks)
+	for _, id := range m.gamepads {
+		if direction, ok := stickDirection(id); ok {
+			m.sticks[id] = direction
+		}
+	}
+}
+
+// JustPressed сообщает, что на этом кадре нажата клавиша действия или его кнопка на любом геймпаде.
+func (m *Map) JustPressed(action string) bool {
+	if key, ok := m.keys[action]; ok && inpututil.IsKeyJustPressed(key) {
+		return true
+	}
+	button, ok := m.buttons[action]
+	if !ok {
+		return false
+	}
+	for _, id := range m.gamepads {
+		if inpututil.IsStandardGamepadButtonJustPressed(id, button) {
+			return true
+		}
+	}
+	return false
+}
+
+// Pressed сообщает, что клавиша действия или его кнопка на любом геймпаде сейчас зажата.
+func (m *Map) Pressed(action string) bool {
+	if key, ok := m.keys[action]; ok && ebiten.IsKeyPressed(key) {
+		return true
+	}
+	button, ok := m.buttons[action]
+	if !ok {
+		return false
+	}
+	for _, id := range m.gamepads {
+		if ebiten.IsStandardGamepadButtonPressed(id, button) {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyName возвращает имя клавиши, назначенной действию, например "ArrowUp"; пустая строка —
+// действие неизвестно.
+func (m *Map) KeyName(action string) string {
+	key, ok := m.keys[action]
+	if !ok {
+		return ""
+	}
+	return key.String()
+}
+
+// Turns возвращает повороты, выбранные на этом кадре игроком-человеком player: клавишами
+// игрока (первый и второй набор по очереди) и его геймпадом. Нажатые вместе клавиши
+// дают несколько поворотов, их разберёт очередь змеи.
+func (m *Map) Turns(player int) []core.Direction {
+	turns := make([]core.Direction, 0)
+	actions := config.PlayerActions(player % 2)
+	for i, action := range actions {
+		if inpututil.IsKeyJustPressed(m.keys[action]) {
+			turns = append(turns, directions[i])
+		}
+	}
+	if player < len(m.gamepads) {
+		if direction, ok := m.gamepadTurn(m.gamepads[player], actions); ok {
+			turns = append(turns, direction)
+		}
+	}
+	return turns
+}
+
+// MenuDirection возвращает направление для перемещения по меню: стрелки, клавиши любого
+// игрока или любой геймпад.
+func (m *Map) MenuDirection() (core.Direction, bool) {
+	for i, key := range menuKeys {
+		if inpututil.IsKeyJustPressed(key) {
+			return directions[i], true
+		}
+	}
+	for player := range 2 {
+		for i, action := range config.PlayerActions(player) {
+			if inpututil.IsKeyJustPressed(m.keys[action]) {
+				return directions[i], true
+			}
+		}
+	}
+	for _, id := range m.gamepads {
+		if direction, ok := m.gamepadTurn(id, config.PlayerActions(0)); ok {
+			return direction, true
+		}
+	}
+	return 0, false
+}
+
+func (m *Map) gamepadTurn(id ebiten.GamepadID, actions []string) (core.Direction, bool) {
+	for i, action := range actions {
+		if button, ok := m.buttons[action]; ok && inpututil.IsStandardGamepadButtonJustPressed(id, button) {
+			return directions[i], true
+		}
+	}
+	// стик срабатывает один раз, когда его отклоняют в новую сторону
+	if direction, ok := m.sticks[id]; ok {
+		if previous, was := m.previousSticks[id]; !was || previous != direction {
+			return direction, true
+		}
+	}
+	return 0, false
+}
+
+// JustPressedButton возвращает имя кнопки, только что нажатой на любом геймпаде,
+// например чтобы назначить её действию.
+func (m *Map) JustPressedButton() (string, bool) {
+	for _, id := range m.gamepads {
+		for _, name := range config.GamepadButtons() {
+			if inpututil.IsStandardGamepadButtonJustPressed(id, buttons[name]) {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Gamepads — сколько подключено геймпадов со стандартной раскладкой.
+func (m *Map) Gamepads() int {
+	return len(m.gamepads)
+}
+
+func stickDirection(id ebiten.GamepadID) (core.Direction, bool) {
+	x := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+	y := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical)
+	switch {
+	case max(x*x, y*y) < stickThreshold*stickThreshold:
+		return 0, false
+	case x*x > y*y && x > 0:
+		return core.Right, true
+	case x*x > y*y:
+		return core.Left, true
+	case y > 0:
+		return core.Down, true
+	default:
+		return core.Up, true
+	}
+}
@@ -0,0 +1,305 @@
+package levelgen
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"snake-game/internal/core"
+	"snake-game/internal/levels"
+	"strings"
+)
+
+// Стили расстановки стен
+const (
+	// StyleScatter — отдельные стены, разбросанные по полю
+	StyleScatter = "scatter"
+	// StyleMaze — лабиринт из коридоров шириной в клетку
+	StyleMaze = "maze"
+	// StyleRooms — комнаты, разделённые стенами с дверями
+	StyleRooms = "rooms"
+)
+
+const (
+	MinWidth  = 8
+	MinHeight = 6
+	// spawnLength — сколько клеток за головой освобождается под тело змеи
+	spawnLength = 3
+	// spawnRunway — сколько клеток перед головой освобождается, чтобы змея не врезалась сразу
+	spawnRunway = 3
+)
+
+func Styles() []string {
+	return []string{StyleScatter, StyleMaze, StyleRooms}
+}
+
+// Params — параметры генерации. Одинаковые параметры всегда дают один и тот же уровень.
+type Params struct {
+	Seed   uint64
+	Width  int
+	Height int
+	// Density — желаемая доля стен среди всех клеток, от 0 до 1
+	Density float64
+	Style   string
+	// Symmetric делает поле центрально-симметричным, чтобы двум игрокам было одинаково
+	Symmetric bool
+	// Name — имя уровня; по умолчанию составляется из стиля и зерна
+	Name string
+}
+
+func (p Params) Validate() error {
+	problems := make([]string, 0)
+	if p.Width < MinWidth || p.Height < MinHeight {
+		problems = append(problems, fmt.Sprintf("grid %dx%d is smaller than %dx%d", p.Width, p.Height, MinWidth, MinHeight))
+	}
+	if p.Width > levels.MaxGridSize || p.Height > levels.MaxGridSize {
+		problems = append(problems, fmt.Sprintf("grid %dx%d is larger than %dx%d", p.Width, p.Height, levels.MaxGridSize, levels.MaxGridSize))
+	}
+	if p.Density < 0 || p.Density > 1 {
+		problems = append(problems, fmt.Sprintf("density %.2f must be between 0 and 1", p.Density))
+	}
+	switch p.Style {
+	case StyleScatter, StyleMaze, StyleRooms:
+	default:
+		problems = append(problems, fmt.Sprintf("unknown style %q: expected one of %s", p.Style, strings.Join(Styles(), ", ")))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid generator parameters: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// DefaultName — имя уровня по умолчанию, например "maze_42".
+func (p Params) DefaultName() string {
+	return fmt.Sprintf("%s_%d", p.Style, p.Seed)
+}
+
+// Generate создаёт уровень по параметрам. Уровень всегда играбелен: у обоих стартов
+// есть место под змею и разгон, а все свободные клетки связаны между собой.
+func Generate(params Params) (*core.Level, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		params: params,
+		rng:    rand.New(rand.NewPCG(params.Seed, params.Seed^0x9e3779b97f4a7c15)),
+		width:  params.Width,
+		height: params.Height,
+		walls:  make([]bool, params.Width*params.Height),
+	}
+
+	switch params.Style {
+	case StyleScatter:
+		g.scatter()
+	case StyleMaze:
+		g.maze()
+	case StyleRooms:
+		g.rooms()
+	}
+	if params.Symmetric {
+		g.symmetrize()
+	}
+
+	spawns := g.spawns()
+	for _, spawn := range spawns {
+		g.clearSpawn(spawn)
+	}
+	g.connect(spawns[0].Position)
+
+	name := params.Name
+	if name == "" {
+		name = params.DefaultName()
+	}
+	level := core.NewLevel(name, params.Width, params.Height, g.wallList())
+	level.Author = "levelgen"
+	level.Description = g.description()
+	level.Difficulty = g.difficulty()
+	level.Spawns = spawns
+
+	// генератор гарантирует играбельность, проверка ловит ошибки в нём самом
+	if err := levels.Validate(level); err != nil {
+		return nil, fmt.Errorf("generated level is not playable: %w", err)
+	}
+	return level, nil
+}
+
+type generator struct {
+	params Params
+	rng    *rand.Rand
+
+	width  int
+	height int
+	walls  []bool
+}
+
+func (g *generator) index(pos core.Position) int {
+	return pos.Y*g.width + pos.X
+}
+
+func (g *generator) isInside(pos core.Position) bool {
+	return pos.X >= 0 && pos.X < g.width && pos.Y >= 0 && pos.Y < g.height
+}
+
+func (g *generator) isWall(pos core.Position) bool {
+	return g.walls[g.index(pos)]
+}
+
+// set ставит или убирает стену; на симметричном поле — вместе с симметричной клеткой,
+// чтобы исправления не ломали симметрию.
+func (g *generator) set(pos core.Position, wall bool) {
+	g.walls[g.index(pos)] = wall
+	if g.params.Symmetric {
+		g.walls[g.index(g.opposite(pos))] = wall
+	}
+}
+
+// opposite — клетка, центрально-симметричная pos.
+func (g *generator) opposite(pos core.Position) core.Position {
+	return core.Position{X: g.width - 1 - pos.X, Y: g.height - 1 - pos.Y}
+}
+
+func (g *generator) wallShare() float64 {
+	count := 0
+	for _, wall := range g.walls {
+		if wall {
+			count++
+		}
+	}
+	return float64(count) / float64(len(g.walls))
+}
+
+func (g *generator) wallList() []core.Wall {
+	walls := make([]core.Wall, 0)
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			if g.walls[y*g.width+x] {
+				walls = append(walls, *core.NewWall(x, y))
+			}
+		}
+	}
+	return walls
+}
+
+// symmetrize копирует первую половину поля во вторую поворотом на 180 градусов.
+func (g *generator) symmetrize() {
+	for i := 0; i < len(g.walls)/2; i++ {
+		g.walls[len(g.walls)-1-i] = g.walls[i]
+	}
+}
+
+// spawns — старты двух игроков: первый слева вверху лицом вправо, второй симметрично ему.
+func (g *generator) spawns() []core.Spawn {
+	first := core.Position{X: spawnLength, Y: g.height / 3}
+	return []core.Spawn{
+		{Position: first, Direction: core.Right},
+		{Position: g.opposite(first), Direction: core.Left},
+	}
+}
+
+// clearSpawn освобождает клетки под тело змеи и разгон перед головой.
+func (g *generator) clearSpawn(spawn core.Spawn) {
+	dx, dy := spawn.Direction.Delta()
+	for i := -spawnLength; i <= spawnRunway; i++ {
+		pos := core.Position{X: spawn.X + i*dx, Y: spawn.Y + i*dy}
+		if g.isInside(pos) {
+			g.set(pos, false)
+		}
+	}
+}
+
+// connect прорубает проходы, пока все свободные клетки не станут достижимы из start.
+func (g *generator) connect(start core.Position) {
+	for {
+		reached := g.flood(start)
+		unreached, found := g.firstFreeCell(func(pos core.Position) bool { return !reached[pos] })
+		if !found {
+			return
+		}
+		for _, pos := range g.pathTo(unreached, reached) {
+			g.set(pos, false)
+		}
+	}
+}
+
+func (g *generator) flood(start core.Position) map[core.Position]bool {
+	reached := map[core.Position]bool{start: true}
+	queue := []core.Position{start}
+	for len(queue) > 0 {
+		pos := queue[0]
+		queue = queue[1:]
+		for _, next := range g.neighbors(pos) {
+			if !g.isWall(next) && !reached[next] {
+				reached[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return reached
+}
+
+func (g *generator) firstFreeCell(match func(core.Position) bool) (core.Position, bool) {
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			pos := core.Position{X: x, Y: y}
+			if !g.isWall(pos) && match(pos) {
+				return pos, true
+			}
+		}
+	}
+	return core.Position{}, false
+}
+
+// pathTo — кратчайший путь от from до ближайшей клетки из targets сквозь стены.
+func (g *generator) pathTo(from core.Position, targets map[core.Position]bool) []core.Position {
+	previous := map[core.Position]core.Position{from: from}
+	queue := []core.Position{from}
+	for len(queue) > 0 {
+		pos := queue[0]
+		queue = queue[1:]
+		if targets[pos] {
+			path := make([]core.Position, 0)
+			for pos != from {
+				path = append(path, pos)
+				pos = previous[pos]
+			}
+			return path
+		}
+		for _, next := range g.neighbors(pos) {
+			if _, seen := previous[next]; !seen {
+				previous[next] = pos
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+func (g *generator) neighbors(pos core.Position) []core.Position {
+	result := make([]core.Position, 0, 4)
+	for _, direction := range []core.Direction{core.Up, core.Down, core.Left, core.Right} {
+		if next := pos.Move(direction); g.isInside(next) {
+			result = append(result, next)
+		}
+	}
+	return result
+}
+
+func (g *generator) description() string {
+	description := fmt.Sprintf("Generated: style %s, seed %d, density %.2f", g.params.Style, g.params.Seed, g.params.Density)
+	if g.params.Symmetric {
+		description += ", symmetric"
+	}
+	return description
+}
+
+func (g *generator) difficulty() string {
+	share := g.wallShare()
+	switch {
+	case share < 0.1:
+		return core.DifficultyEasy
+	case share < 0.2:
+		return core.DifficultyNormal
+	case share < 0.35:
+		return core.DifficultyHard
+	}
+	return core.DifficultyExpert
+}
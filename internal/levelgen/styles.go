@@ -0,0 +1,149 @@
+package levelgen
+
+import "snake-game/internal/core"
+
+const (
+	// minRoomSide — комната меньше этого размера больше не делится
+	minRoomSide = 7
+	// doorWidth — ширина двери в стене между комнатами
+	doorWidth = 2
+	// longWall — на стене длиннее этого делается вторая дверь
+	longWall = 12
+)
+
+// scatter ставит стену в каждую клетку с вероятностью Density.
+func (g *generator) scatter() {
+	for i := range g.walls {
+		g.walls[i] = g.rng.Float64() < g.params.Density
+	}
+}
+
+// maze строит лабиринт обходом в глубину: клетки с чётными координатами — комнаты,
+// между ними прорубаются проходы. Затем случайные стены убираются, пока их доля
+// не опустится до Density: получаются петли, и змея не застревает в тупиках.
+func (g *generator) maze() {
+	for i := range g.walls {
+		g.walls[i] = true
+	}
+	// при чётной стороне крайний ряд не попадает в сетку комнат, он остаётся коридором
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			if x == g.width-1 && x%2 == 1 || y == g.height-1 && y%2 == 1 {
+				g.walls[y*g.width+x] = false
+			}
+		}
+	}
+
+	start := core.Position{X: 0, Y: 0}
+	g.walls[g.index(start)] = false
+	stack := []core.Position{start}
+	for len(stack) > 0 {
+		pos := stack[len(stack)-1]
+		next := make([]core.Position, 0, 4)
+		for _, direction := range []core.Direction{core.Up, core.Down, core.Left, core.Right} {
+			dx, dy := direction.Delta()
+			candidate := core.Position{X: pos.X + 2*dx, Y: pos.Y + 2*dy}
+			if g.isInside(candidate) && g.isWall(candidate) {
+				next = append(next, candidate)
+			}
+		}
+		if len(next) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		chosen := next[g.rng.IntN(len(next))]
+		g.walls[g.index(core.Position{X: (pos.X + chosen.X) / 2, Y: (pos.Y + chosen.Y) / 2})] = false
+		g.walls[g.index(chosen)] = false
+		stack = append(stack, chosen)
+	}
+
+	g.thin()
+}
+
+// thin убирает случайные стены, пока их доля больше Density.
+func (g *generator) thin() {
+	walls := make([]int, 0)
+	for i, wall := range g.walls {
+		if wall {
+			walls = append(walls, i)
+		}
+	}
+	g.rng.Shuffle(len(walls), func(i, j int) {
+		walls[i], walls[j] = walls[j], walls[i]
+	})
+
+	target := int(g.params.Density * float64(len(g.walls)))
+	for count := len(walls); count > target; count-- {
+		g.walls[walls[count-1]] = false
+	}
+}
+
+// room — прямоугольная часть поля, которую ещё можно разделить.
+type room struct {
+	x, y, width, height int
+}
+
+// rooms делит поле стенами с дверями (рекурсивное деление), начиная с самых больших
+// комнат, пока доля стен не достигнет Density или комнаты не станут слишком малы.
+func (g *generator) rooms() {
+	queue := []room{{x: 0, y: 0, width: g.width, height: g.height}}
+	for len(queue) > 0 && g.wallShare() < g.params.Density {
+		current := queue[0]
+		queue = queue[1:]
+
+		vertical := current.width > current.height
+		if current.width == current.height {
+			vertical = g.rng.IntN(2) == 0
+		}
+		if vertical && current.width < minRoomSide {
+			vertical = false
+		}
+		if !vertical && current.height < minRoomSide {
+			if current.width < minRoomSide {
+				continue
+			}
+			vertical = true
+		}
+
+		if vertical {
+			x := current.x + 2 + g.rng.IntN(current.width-4)
+			g.wallWithDoors(core.Position{X: x, Y: current.y}, core.Down, current.height)
+			queue = append(queue,
+				room{x: current.x, y: current.y, width: x - current.x, height: current.height},
+				room{x: x + 1, y: current.y, width: current.x + current.width - x - 1, height: current.height},
+			)
+		} else {
+			y := current.y + 2 + g.rng.IntN(current.height-4)
+			g.wallWithDoors(core.Position{X: current.x, Y: y}, core.Right, current.width)
+			queue = append(queue,
+				room{x: current.x, y: current.y, width: current.width, height: y - current.y},
+				room{x: current.x, y: y + 1, width: current.width, height: current.y + current.height - y - 1},
+			)
+		}
+	}
+}
+
+// wallWithDoors ставит стену длиной length от start в направлении direction
+// и оставляет в ней одну или две двери.
+func (g *generator) wallWithDoors(start core.Position, direction core.Direction, length int) {
+	doors := make(map[int]bool)
+	doorCount := 1
+	if length > longWall {
+		doorCount = 2
+	}
+	for i := 0; i < doorCount; i++ {
+		// двери разносятся по своим частям стены, чтобы не слипаться
+		section := length / doorCount
+		door := i*section + g.rng.IntN(max(section-doorWidth+1, 1))
+		for j := 0; j < doorWidth; j++ {
+			doors[door+j] = true
+		}
+	}
+
+	dx, dy := direction.Delta()
+	for i := 0; i < length; i++ {
+		if !doors[i] {
+			g.walls[g.index(core.Position{X: start.X + i*dx, Y: start.Y + i*dy})] = true
+		}
+	}
+}
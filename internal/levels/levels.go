@@ -0,0 +1,125 @@
+package levels
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"snake-game/internal/core"
+	"strings"
+)
+
+const (
+	Dir = "levels"
+	Ext = ".json"
+)
+
+// Entry — файл уровня в каталоге. Err — причина, по которой в уровень нельзя играть:
+// файл не читается, не разбирается или не проходит проверку.
+type Entry struct {
+	Path  string
+	Level *core.Level
+	Err   error
+	// VersusErr — причина, по которой в уровень нельзя играть вдвоём, например старт
+	// второго игрока по умолчанию попал на стену; для неисправного уровня совпадает с Err
+	VersusErr error
+}
+
+// Name — имя файла без расширения, под которым уровень показывается в меню.
+func (e Entry) Name() string {
+	return strings.TrimSuffix(filepath.Base(e.Path), Ext)
+}
+
+func (e Entry) IsValid() bool {
+	return e.Err == nil
+}
+
+// ErrFor — причина, по которой в уровень нельзя играть players игрокам; nil — можно.
+func (e Entry) ErrFor(players int) error {
+	if players > 1 {
+		return e.VersusErr
+	}
+	return e.Err
+}
+
+// NewEntry проверяет уровень из файла path и для одного игрока, и для игры вдвоём.
+func NewEntry(path string) Entry {
+	level, err := LoadValid(path)
+	entry := Entry{Path: path, Level: level, Err: err, VersusErr: err}
+	if err == nil {
+		entry.VersusErr = ValidatePlayers(level, VersusPlayers)
+	}
+	return entry
+}
+
+// Load читает и разбирает файл уровня, не проверяя его.
+func Load(path string) (*core.Level, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read level file: %w", err)
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return nil, fmt.Errorf("level file is empty")
+	}
+	level, err := core.ParseLevel(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse level: %w", err)
+	}
+	return level, nil
+}
+
+// LoadValid читает уровень и проверяет его.
+func LoadValid(path string) (*core.Level, error) {
+	level, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := Validate(level); err != nil {
+		return level, err
+	}
+	return level, nil
+}
+
+// Scan находит все уровни в dir и проверяет каждый; порядок — по имени файла.
+func Scan(dir string) ([]Entry, error) {
+	entries := make([]Entry, 0)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), Ext) {
+			return nil
+		}
+		entries = append(entries, NewEntry(path))
+		return nil
+	})
+	return entries, err
+}
+
+// Save проверяет уровень и записывает его в path в текущей версии формата.
+// Файл сначала пишется во временный, поэтому при ошибке старый уровень не портится.
+func Save(path string, level *core.Level) error {
+	if err := Validate(level); err != nil {
+		return err
+	}
+
+	saved := *level
+	saved.Version = core.CurrentLevelVersion
+	data, err := json.MarshalIndent(&saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal level: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create levels directory: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write level file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace level file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package levels
+
+import (
+	"snake-game/internal/core"
+	"strings"
+)
+
+// Символы текстовой картинки уровня
+const (
+	RenderEmpty = '.'
+	RenderWall  = '#'
+	RenderFood  = '*'
+	// RenderZone — клетка зоны появления еды
+	RenderZone = '+'
+)
+
+// Render рисует уровень текстом: строка на ряд клеток, стены, зоны и клетки еды.
+// Старты игроков отмечаются номером игрока, начиная с 1. Клетки за краем поля пропускаются,
+// поэтому нарисовать можно и неисправный уровень.
+func Render(level *core.Level) string {
+	if level.GridWidth <= 0 || level.GridHeight <= 0 {
+		return ""
+	}
+	grid := make([][]rune, level.GridHeight)
+	for y := range grid {
+		grid[y] = []rune(strings.Repeat(string(RenderEmpty), level.GridWidth))
+	}
+	set := func(pos core.Position, cell rune) {
+		if pos.X >= 0 && pos.X < level.GridWidth && pos.Y >= 0 && pos.Y < level.GridHeight {
+			grid[pos.Y][pos.X] = cell
+		}
+	}
+
+	if level.Food != nil {
+		for _, zone := range level.Food.Zones {
+			for y := zone.Y; y < zone.Y+zone.Height; y++ {
+				for x := zone.X; x < zone.X+zone.Width; x++ {
+					set(core.Position{X: x, Y: y}, RenderZone)
+				}
+			}
+		}
+		for _, pos := range level.Food.Fixed {
+			set(pos, RenderFood)
+		}
+	}
+	for _, wall := range level.Walls {
+		set(wall.Position, RenderWall)
+	}
+	for i, spawn := range level.Spawns {
+		set(spawn.Position, rune('1'+i%9))
+	}
+
+	var builder strings.Builder
+	for _, row := range grid {
+		builder.WriteString(string(row))
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
@@ -0,0 +1,266 @@
+package levels
+
+import (
+	"errors"
+	"fmt"
+	"snake-game/internal/core"
+	"snake-game/internal/sim"
+	"strings"
+)
+
+const (
+	// MaxGridSize — предел стороны поля; в окно поле любого размера вписывается само
+	MaxGridSize = 200
+	// maxReportedCells — сколько клеток перечислять в одной проблеме
+	maxReportedCells = 5
+	// VersusPlayers — сколько игроков в игре вдвоём за одной клавиатурой и против бота
+	VersusPlayers = 2
+)
+
+// ValidationError перечисляет все найденные в уровне проблемы.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid level: " + strings.Join(e.Problems, "; ")
+}
+
+// Reason — первая проблема, коротко для показа в меню.
+func (e *ValidationError) Reason() string {
+	if len(e.Problems) == 0 {
+		return ""
+	}
+	if len(e.Problems) == 1 {
+		return e.Problems[0]
+	}
+	return fmt.Sprintf("%s (+%d more)", e.Problems[0], len(e.Problems)-1)
+}
+
+// Reason — короткое описание ошибки загрузки или проверки уровня для показа в меню.
+func Reason(err error) string {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Reason()
+	}
+	return err.Error()
+}
+
+type validator struct {
+	level    *core.Level
+	walls    map[core.Position]bool
+	problems []string
+}
+
+func (v *validator) addf(format string, args ...any) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) isInside(pos core.Position) bool {
+	return pos.X >= 0 && pos.X < v.level.GridWidth && pos.Y >= 0 && pos.Y < v.level.GridHeight
+}
+
+func (v *validator) isFree(pos core.Position) bool {
+	return v.isInside(pos) && !v.walls[pos]
+}
+
+// Validate проверяет уровень целиком для одного игрока и возвращает *ValidationError
+// со всеми проблемами.
+func Validate(level *core.Level) error {
+	return ValidatePlayers(level, 1)
+}
+
+// ValidatePlayers проверяет уровень для игры players игроков: старты всех игроков,
+// включая старты по умолчанию для тех, кого в уровне нет.
+func ValidatePlayers(level *core.Level, players int) error {
+	if level == nil {
+		return &ValidationError{Problems: []string{"level is nil"}}
+	}
+	v := &validator{level: level, walls: make(map[core.Position]bool, len(level.Walls))}
+
+	v.checkSize()
+	if level.GridWidth > 0 && level.GridHeight > 0 && level.GridWidth <= MaxGridSize && level.GridHeight <= MaxGridSize {
+		v.checkWalls()
+		v.checkSpawns(players)
+		v.checkFood()
+	}
+	v.checkMetadata()
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+func (v *validator) checkSize() {
+	width, height := v.level.GridWidth, v.level.GridHeight
+	if width <= 0 || height <= 0 {
+		v.addf("grid size %dx%d must be positive", width, height)
+		return
+	}
+	if width > MaxGridSize || height > MaxGridSize {
+		v.addf("grid %dx%d is larger than %dx%d", width, height, MaxGridSize, MaxGridSize)
+	}
+}
+
+func (v *validator) checkWalls() {
+	outside := make([]string, 0)
+	duplicates := make([]string, 0)
+	for _, wall := range v.level.Walls {
+		switch {
+		case !v.isInside(wall.Position):
+			outside = append(outside, cellName(wall.Position))
+		case v.walls[wall.Position]:
+			duplicates = append(duplicates, cellName(wall.Position))
+		default:
+			v.walls[wall.Position] = true
+		}
+	}
+	if len(outside) > 0 {
+		v.addf("%d walls out of bounds: %s", len(outside), cellList(outside))
+	}
+	if len(duplicates) > 0 {
+		v.addf("%d duplicate walls: %s", len(duplicates), cellList(duplicates))
+	}
+	if len(v.walls) >= v.level.GridWidth*v.level.GridHeight {
+		v.addf("no free cell: the whole grid is walls")
+	}
+}
+
+// checkSpawns проверяет старт каждого игрока из уровня и старты по умолчанию для
+// остальных из players: змея целиком на свободных клетках и не задевает других змей,
+// ей есть куда шагнуть, и с её места достижимы все свободные клетки.
+func (v *validator) checkSpawns(players int) {
+	players = max(len(v.level.Spawns), players, 1)
+	// owners — какой игрок стоит на клетке, чтобы найти наложившиеся старты
+	owners := make(map[core.Position]int)
+	// длина змеи, если уровень её не задаёт: меньше не бывает
+	length := core.MinSnakeLength
+	if v.level.InitialLength > 0 {
+		length = v.level.InitialLength
+	}
+	if v.level.InitialLength < 0 {
+		v.addf("initial length %d must not be negative", v.level.InitialLength)
+	}
+
+	for player := 0; player < players; player++ {
+		head, direction := sim.SpawnPoint(v.level, player, players)
+		name := fmt.Sprintf("spawn %d at %s", player+1, cellName(head))
+
+		if !v.isFree(head) {
+			if v.isInside(head) {
+				v.addf("%s is on a wall", name)
+			} else {
+				v.addf("%s is out of bounds", name)
+			}
+			continue
+		}
+
+		dx, dy := direction.Delta()
+		for i := 1; i < length; i++ {
+			segment := core.Position{X: head.X - i*dx, Y: head.Y - i*dy}
+			if !v.isFree(segment) {
+				v.addf("%s: snake of length %d does not fit behind the head facing %s", name, length, direction)
+				break
+			}
+		}
+		for i := 0; i < length; i++ {
+			segment := core.Position{X: head.X - i*dx, Y: head.Y - i*dy}
+			if other, taken := owners[segment]; taken {
+				v.addf("%s overlaps the snake of spawn %d", name, other+1)
+				break
+			}
+			owners[segment] = player
+		}
+
+		exits := 0
+		for _, turn := range []core.Direction{core.Up, core.Down, core.Left, core.Right} {
+			if turn != direction.Opposite() && v.isFree(head.Move(turn)) {
+				exits++
+			}
+		}
+		if exits == 0 {
+			v.addf("%s is boxed in: no free cell to move to", name)
+		}
+
+		if unreachable := v.unreachableFrom(head); unreachable > 0 {
+			v.addf("%d free cells are unreachable from %s", unreachable, name)
+		}
+	}
+}
+
+// unreachableFrom считает свободные клетки, до которых нельзя дойти из start.
+func (v *validator) unreachableFrom(start core.Position) int {
+	visited := map[core.Position]bool{start: true}
+	queue := []core.Position{start}
+	for len(queue) > 0 {
+		pos := queue[0]
+		queue = queue[1:]
+		for _, direction := range []core.Direction{core.Up, core.Down, core.Left, core.Right} {
+			next := pos.Move(direction)
+			if v.isFree(next) && !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	free := v.level.GridWidth*v.level.GridHeight - len(v.walls)
+	return free - len(visited)
+}
+
+func (v *validator) checkFood() {
+	food := v.level.Food
+	if food == nil {
+		return
+	}
+	for _, cell := range food.Fixed {
+		if !v.isFree(cell) {
+			v.addf("fixed food cell %s is not a free cell", cellName(cell))
+		}
+	}
+	for i, zone := range food.Zones {
+		if zone.Width <= 0 || zone.Height <= 0 {
+			v.addf("food zone %d has empty size %dx%d", i+1, zone.Width, zone.Height)
+			continue
+		}
+		if zone.Weight < 0 {
+			v.addf("food zone %d has negative weight %d", i+1, zone.Weight)
+		}
+		hasFreeCell := false
+		for x := zone.X; x < zone.X+zone.Width && !hasFreeCell; x++ {
+			for y := zone.Y; y < zone.Y+zone.Height && !hasFreeCell; y++ {
+				hasFreeCell = v.isFree(core.Position{X: x, Y: y})
+			}
+		}
+		if !hasFreeCell {
+			v.addf("food zone %d has no free cell", i+1)
+		}
+	}
+}
+
+func (v *validator) checkMetadata() {
+	for i, point := range v.level.SpeedCurve {
+		if point.Score < 0 || point.Interval <= 0 {
+			v.addf("speed curve point %d must have non-negative score and positive interval", i+1)
+		}
+	}
+	switch v.level.Difficulty {
+	case "", core.DifficultyEasy, core.DifficultyNormal, core.DifficultyHard, core.DifficultyExpert:
+	default:
+		v.addf("unknown difficulty %q", v.level.Difficulty)
+	}
+	if strings.TrimSpace(v.level.Name) == "" {
+		v.addf("level name is empty")
+	}
+}
+
+func cellName(pos core.Position) string {
+	return fmt.Sprintf("(%d, %d)", pos.X, pos.Y)
+}
+
+func cellList(cells []string) string {
+	if len(cells) > maxReportedCells {
+		return strings.Join(cells[:maxReportedCells], ", ") + ", ..."
+	}
+	return strings.Join(cells, ", ")
+}
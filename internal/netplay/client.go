@@ -0,0 +1,126 @@
+package netplay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"snake-game/internal/core"
+	"snake-game/internal/sim"
+	"sync"
+)
+
+// Client — подключение игрока к серверу. Состояние мира читается в фоне,
+// игра забирает последний снимок через State.
+type Client struct {
+	conn    net.Conn
+	enc     *json.Encoder
+	welcome Welcome
+
+	mu       sync.Mutex
+	state    State
+	hasState bool
+	err      error
+}
+
+// Dial подключается к серверу и ждёт, пока соберутся все игроки.
+// Ожидание прерывается отменой ctx.
+func Dial(ctx context.Context, addr, name string) (*Client, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	defer stop()
+
+	c := &Client{conn: conn, enc: json.NewEncoder(conn)}
+	dec := json.NewDecoder(conn)
+
+	if err := c.enc.Encode(Message{Type: HelloMessage, Hello: &Hello{Version: ProtocolVersion, Name: name}}); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to send hello: %w", err)
+	}
+
+	var msg Message
+	if err := dec.Decode(&msg); err != nil {
+		_ = conn.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to read welcome: %w", err)
+	}
+	switch {
+	case msg.Type == ErrorMessage:
+		_ = conn.Close()
+		return nil, fmt.Errorf("server rejected connection: %s", msg.Error)
+	case msg.Type != WelcomeMessage || msg.Welcome == nil:
+		_ = conn.Close()
+		return nil, fmt.Errorf("unexpected message %q: expected %q", msg.Type, WelcomeMessage)
+	}
+	c.welcome = *msg.Welcome
+
+	go c.read(dec)
+	return c, nil
+}
+
+func (c *Client) read(dec *json.Decoder) {
+	for {
+		var msg Message
+		if err := dec.Decode(&msg); err != nil {
+			c.mu.Lock()
+			c.err = err
+			c.mu.Unlock()
+			return
+		}
+		if msg.Type == StateMessage && msg.State != nil {
+			c.mu.Lock()
+			c.state = *msg.State
+			c.hasState = true
+			c.mu.Unlock()
+		}
+	}
+}
+
+func (c *Client) Welcome() Welcome {
+	return c.welcome
+}
+
+// State возвращает последний полученный снимок; false — снимков ещё не было.
+func (c *Client) State() (State, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state, c.hasState
+}
+
+// Err возвращает ошибку чтения, после которой снимки больше не приходят.
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// SendTurn планирует поворот на InputDelay тиков позже последнего известного тика сервера.
+func (c *Client) SendTurn(direction core.Direction) error {
+	state, _ := c.State()
+	input := Input{Tick: state.Tick + c.welcome.InputDelay, Direction: direction}
+	if err := c.enc.Encode(Message{Type: InputMessage, Input: &input}); err != nil {
+		return fmt.Errorf("failed to send input: %w", err)
+	}
+	return nil
+}
+
+// NewWorld строит локальный мир по параметрам сервера; дальше он обновляется только снимками.
+func (c *Client) NewWorld() (*sim.World, error) {
+	mode, err := sim.ModeByName(c.welcome.Mode)
+	if err != nil {
+		return nil, err
+	}
+	return sim.NewWorld(&c.welcome.Level, c.welcome.Rules, mode, c.welcome.Players, c.welcome.Seed)
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,124 @@
+package netplay
+
+import (
+	"snake-game/internal/core"
+	"snake-game/internal/sim"
+)
+
+// Сообщения передаются по TCP в виде JSON, по одному объекту на строку.
+const (
+	ProtocolVersion = 1
+
+	DefaultAddr       = "localhost:7777"
+	DefaultInputDelay = 3
+
+	HelloMessage   = "hello"
+	WelcomeMessage = "welcome"
+	InputMessage   = "input"
+	StateMessage   = "state"
+	ErrorMessage   = "error"
+)
+
+type Message struct {
+	Type    string   `json:"type"`
+	Hello   *Hello   `json:"hello,omitempty"`
+	Welcome *Welcome `json:"welcome,omitempty"`
+	Input   *Input   `json:"input,omitempty"`
+	State   *State   `json:"state,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// Hello — первое сообщение клиента после подключения.
+type Hello struct {
+	Version int    `json:"version"`
+	Name    string `json:"name,omitempty"`
+}
+
+// Welcome отправляется каждому клиенту, когда подключились все игроки.
+// По нему клиент строит у себя такой же мир, как на сервере.
+type Welcome struct {
+	Player     int        `json:"player"`
+	Players    int        `json:"players"`
+	Names      []string   `json:"names"`
+	Level      core.Level `json:"level"`
+	Rules      sim.Rules  `json:"rules"`
+	Mode       string     `json:"mode"`
+	Seed       uint64     `json:"seed"`
+	InputDelay int        `json:"input_delay"`
+}
+
+// Input — поворот игрока, который сервер применит на тике Tick.
+// Если ввод опоздал, сервер применяет его на ближайшем тике.
+type Input struct {
+	Tick      int            `json:"tick"`
+	Direction core.Direction `json:"direction"`
+}
+
+type SnakeState struct {
+	Body      []core.Position `json:"body"`
+	Direction core.Direction  `json:"direction"`
+	IsAlive   bool            `json:"alive"`
+}
+
+// State — снимок мира на сервере после очередного тика.
+type State struct {
+	Tick   int              `json:"tick"`
+	Snakes []SnakeState     `json:"snakes"`
+	Food   *core.Position   `json:"food,omitempty"`
+	Scores []int            `json:"scores"`
+	Causes []sim.DeathCause `json:"causes"`
+	Over   bool             `json:"over,omitempty"`
+	Reason sim.DeathCause   `json:"reason,omitempty"`
+	Winner int              `json:"winner,omitempty"`
+}
+
+func Snapshot(w *sim.World) State {
+	state := State{
+		Tick:   w.Tick,
+		Snakes: make([]SnakeState, len(w.Snakes)),
+		Scores: append([]int(nil), w.Scores...),
+		Causes: append([]sim.DeathCause(nil), w.Causes...),
+		Over:   w.IsOver(),
+		Reason: w.DeathCause,
+	}
+	for i, snake := range w.Snakes {
+		body := make([]core.Position, len(snake.Body))
+		for j, segment := range snake.Body {
+			body[j] = segment.Position
+		}
+		state.Snakes[i] = SnakeState{Body: body, Direction: snake.Direction, IsAlive: snake.IsAlive}
+	}
+	if w.Food != nil {
+		food := w.Food.Position
+		state.Food = &food
+	}
+	if state.Over {
+		state.Winner = w.Winner()
+	}
+	return state
+}
+
+// Apply переносит снимок в локальный мир клиента, который используется только для отрисовки.
+func (s State) Apply(w *sim.World) {
+	w.Tick = s.Tick
+	for i, snakeState := range s.Snakes {
+		if i >= len(w.Snakes) {
+			break
+		}
+		snake := w.Snakes[i]
+		snake.Body = make([]core.SnakeSegment, len(snakeState.Body))
+		for j, pos := range snakeState.Body {
+			snake.Body[j] = core.SnakeSegment{Position: pos}
+		}
+		snake.Direction = snakeState.Direction
+		snake.ClearTurns()
+		snake.IsAlive = snakeState.IsAlive
+	}
+	w.Food = nil
+	if s.Food != nil {
+		w.Food = core.NewFood(s.Food.X, s.Food.Y)
+	}
+	copy(w.Scores, s.Scores)
+	copy(w.Causes, s.Causes)
+	w.DeathCause = s.Reason
+}
@@ -0,0 +1,298 @@
+package netplay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"slices"
+	"snake-game/internal/core"
+	"snake-game/internal/replay"
+	"snake-game/internal/sim"
+	"time"
+)
+
+const (
+	helloTimeout = 10 * time.Second
+	writeTimeout = time.Second
+
+	// maxInputLead — на сколько тиков сверх InputDelay ввод может опережать сервер.
+	// Ввод дальше отбрасывается: иначе один клиент раздувал бы буфер без ограничений
+	maxInputLead = 10
+	// maxPendingInputs — сколько ещё не применённых поворотов сервер держит на игрока
+	maxPendingInputs = 32
+)
+
+type ServerConfig struct {
+	Level   *core.Level
+	Rules   sim.Rules
+	Mode    sim.GameMode
+	Players int
+	Seed    uint64
+	// InputDelay — на сколько тиков вперёд клиент планирует свои повороты,
+	// чтобы ввод успел дойти до сервера к нужному тику
+	InputDelay int
+}
+
+// Server — авторитетный сервер одной партии: ждёт всех игроков, затем сам
+// продвигает мир и рассылает клиентам его состояние после каждого тика.
+type Server struct {
+	cfg    ServerConfig
+	logger *slog.Logger
+}
+
+type peer struct {
+	player int
+	name   string
+	conn   net.Conn
+	enc    *json.Encoder
+	dec    *json.Decoder
+	isGone bool
+}
+
+// peerInput — ввод или отключение игрока, прочитанные из его соединения.
+type peerInput struct {
+	player   int
+	input    Input
+	isClosed bool
+}
+
+func NewServer(cfg ServerConfig, logger *slog.Logger) (*Server, error) {
+	if cfg.Level == nil {
+		return nil, fmt.Errorf("invalid level: nil")
+	}
+	if cfg.Players < 1 {
+		return nil, fmt.Errorf("invalid number of players: expected positive value, received %d", cfg.Players)
+	}
+	if cfg.Mode == nil {
+		cfg.Mode = sim.ClassicMode{}
+	}
+	if cfg.InputDelay < 0 {
+		return nil, fmt.Errorf("invalid input delay: expected non-negative value, received %d", cfg.InputDelay)
+	}
+	return &Server{cfg: cfg, logger: logger}, nil
+}
+
+// Run принимает игроков на ln, проводит партию и возвращает её повтор.
+// Отмена ctx прерывает ожидание игроков и саму игру.
+func (s *Server) Run(ctx context.Context, ln net.Listener) (*replay.Replay, error) {
+	stop := context.AfterFunc(ctx, func() {
+		_ = ln.Close()
+	})
+	defer stop()
+
+	peers, err := s.accept(ctx, ln)
+	defer func() {
+		for _, p := range peers {
+			_ = p.conn.Close()
+		}
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	world, err := sim.NewWorld(s.cfg.Level, s.cfg.Rules, s.cfg.Mode, s.cfg.Players, s.cfg.Seed)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось создать игровое поле: %w", err)
+	}
+	rep := replay.New(s.cfg.Level, world.Rules(), s.cfg.Mode, s.cfg.Players, world.Seed())
+
+	names := make([]string, len(peers))
+	for i, p := range peers {
+		names[i] = p.name
+	}
+	for _, p := range peers {
+		s.send(p, Message{Type: WelcomeMessage, Welcome: &Welcome{
+			Player:     p.player,
+			Players:    s.cfg.Players,
+			Names:      names,
+			Level:      *s.cfg.Level,
+			Rules:      world.Rules(),
+			Mode:       s.cfg.Mode.Name(),
+			Seed:       world.Seed(),
+			InputDelay: s.cfg.InputDelay,
+		}})
+	}
+
+	received := make(chan peerInput, 64*len(peers))
+	done := make(chan struct{})
+	defer close(done)
+	for _, p := range peers {
+		go s.read(p, received, done)
+	}
+
+	s.logger.Info("game started", "players", names, "seed", world.Seed(), "mode", s.cfg.Mode.Name())
+	s.loop(ctx, world, rep, peers, received)
+
+	rep.Finish(world)
+	s.logger.Info("game finished", "scores", world.Scores, "reason", world.DeathCause.String(), "ticks", world.Tick)
+	return rep, nil
+}
+
+func (s *Server) accept(ctx context.Context, ln net.Listener) ([]*peer, error) {
+	peers := make([]*peer, 0, s.cfg.Players)
+	for len(peers) < s.cfg.Players {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return peers, ctx.Err()
+			}
+			return peers, fmt.Errorf("failed to accept connection: %w", err)
+		}
+
+		p := &peer{
+			player: len(peers),
+			conn:   conn,
+			enc:    json.NewEncoder(conn),
+			dec:    json.NewDecoder(conn),
+		}
+		if err := s.handshake(p); err != nil {
+			s.logger.Warn("client rejected", "addr", conn.RemoteAddr().String(), "error", err)
+			s.send(p, Message{Type: ErrorMessage, Error: err.Error()})
+			_ = conn.Close()
+			continue
+		}
+
+		peers = append(peers, p)
+		s.logger.Info("player joined", "player", p.player, "name", p.name, "addr", conn.RemoteAddr().String(),
+			"waiting_for", s.cfg.Players-len(peers))
+	}
+	return peers, nil
+}
+
+func (s *Server) handshake(p *peer) error {
+	_ = p.conn.SetReadDeadline(time.Now().Add(helloTimeout))
+	defer p.conn.SetReadDeadline(time.Time{})
+
+	var msg Message
+	if err := p.dec.Decode(&msg); err != nil {
+		return fmt.Errorf("failed to read hello: %w", err)
+	}
+	if msg.Type != HelloMessage || msg.Hello == nil {
+		return fmt.Errorf("unexpected message %q: expected %q", msg.Type, HelloMessage)
+	}
+	if msg.Hello.Version != ProtocolVersion {
+		return fmt.Errorf("unsupported protocol version %d: expected %d", msg.Hello.Version, ProtocolVersion)
+	}
+
+	p.name = msg.Hello.Name
+	if p.name == "" {
+		p.name = fmt.Sprintf("P%d", p.player+1)
+	}
+	return nil
+}
+
+func (s *Server) read(p *peer, received chan<- peerInput, done <-chan struct{}) {
+	for {
+		var msg Message
+		in := peerInput{player: p.player}
+		if err := p.dec.Decode(&msg); err != nil {
+			in.isClosed = true
+		} else if msg.Type == InputMessage && msg.Input != nil {
+			in.input = *msg.Input
+		} else {
+			continue
+		}
+
+		select {
+		case received <- in:
+		case <-done:
+			return
+		}
+		if in.isClosed {
+			return
+		}
+	}
+}
+
+// loop продвигает мир с частотой sim.TicksPerSecond. Ввод каждого игрока
+// буферизуется до своего тика, поэтому клиенты с задержкой не теряют повороты.
+func (s *Server) loop(ctx context.Context, world *sim.World, rep *replay.Replay, peers []*peer, received <-chan peerInput) {
+	ticker := time.NewTicker(time.Second / sim.TicksPerSecond)
+	defer ticker.Stop()
+
+	pending := make([][]Input, len(peers))
+	for !world.IsOver() {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for hasMore := true; hasMore; {
+			select {
+			case in := <-received:
+				if in.isClosed {
+					if !peers[in.player].isGone {
+						s.logger.Info("player left", "player", in.player)
+					}
+					peers[in.player].isGone = true
+					continue
+				}
+				pending[in.player] = s.buffer(pending[in.player], in, world.Tick)
+			default:
+				hasMore = false
+			}
+		}
+
+		inputs := make([]sim.Input, len(peers))
+		for player := range pending {
+			pending[player] = s.takeDue(pending[player], world.Tick, &inputs[player])
+			for _, direction := range inputs[player].Turns {
+				rep.Record(world.Tick, player, direction)
+			}
+		}
+		if !slices.ContainsFunc(peers, func(p *peer) bool { return !p.isGone }) {
+			s.logger.Info("all players left, stopping game")
+			inputs[0].Finish = true
+		}
+
+		world.Step(inputs...)
+		state := Snapshot(world)
+		for _, p := range peers {
+			s.send(p, Message{Type: StateMessage, State: &state})
+		}
+	}
+}
+
+// buffer добавляет ввод в буфер игрока, если он запланирован не слишком далеко вперёд
+// и буфер ещё не полон; иначе ввод отбрасывается.
+func (s *Server) buffer(buffer []Input, in peerInput, tick int) []Input {
+	switch {
+	case in.input.Tick > tick+s.cfg.InputDelay+maxInputLead:
+		s.logger.Warn("input dropped: scheduled too far ahead", "player", in.player, "tick", in.input.Tick, "server_tick", tick)
+	case len(buffer) >= maxPendingInputs:
+		s.logger.Warn("input dropped: too many pending inputs", "player", in.player, "pending", len(buffer))
+	default:
+		return append(buffer, in.input)
+	}
+	return buffer
+}
+
+// takeDue переносит в input повороты, запланированные не позже тика tick, и возвращает остаток.
+func (s *Server) takeDue(buffer []Input, tick int, input *sim.Input) []Input {
+	slices.SortStableFunc(buffer, func(a, b Input) int {
+		return a.Tick - b.Tick
+	})
+	due := 0
+	for due < len(buffer) && buffer[due].Tick <= tick {
+		input.Turns = append(input.Turns, buffer[due].Direction)
+		due++
+	}
+	return buffer[due:]
+}
+
+func (s *Server) send(p *peer, msg Message) {
+	if p.isGone {
+		return
+	}
+	_ = p.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err := p.enc.Encode(msg); err != nil {
+		if !errors.Is(err, net.ErrClosed) {
+			s.logger.Warn("failed to send message", "player", p.player, "type", msg.Type, "error", err)
+		}
+		p.isGone = true
+	}
+}
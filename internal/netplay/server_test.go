@@ -0,0 +1,148 @@
+package netplay
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"reflect"
+	"snake-game/internal/core"
+	"snake-game/internal/replay"
+	"snake-game/internal/sim"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T, players int) *Server {
+	t.Helper()
+
+	server, err := NewServer(ServerConfig{
+		Level: core.NewLevel("loopback", 16, 9, nil),
+		Rules: sim.Rules{
+			InitialSnakeLen: 3,
+			InitialSpeed:    6,
+			MaxSpeed:        2,
+			TurnQueue:       sim.TurnQueueSize,
+		},
+		Players:    players,
+		Seed:       42,
+		InputDelay: DefaultInputDelay,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	return server
+}
+
+// TestServerLoopback проводит партию нескольких клиентов через 127.0.0.1 и проверяет,
+// что все они увидели одно и то же итоговое состояние.
+func TestServerLoopback(t *testing.T) {
+	const players = 3
+	server := newTestServer(t, players)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	type result struct {
+		rep *replay.Replay
+		err error
+	}
+	finished := make(chan result, 1)
+	go func() {
+		rep, err := server.Run(ctx, ln)
+		finished <- result{rep, err}
+	}()
+
+	// Dial возвращается только когда подключатся все игроки, поэтому клиенты подключаются параллельно
+	clients := make([]*Client, players)
+	errs := make([]error, players)
+	var wg sync.WaitGroup
+	for i := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clients[i], errs[i] = Dial(ctx, ln.Addr().String(), "")
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("client %d: Dial: %v", i, err)
+		}
+	}
+	defer func() {
+		for _, client := range clients {
+			_ = client.Close()
+		}
+	}()
+
+	turns := []core.Direction{core.Up, core.Down, core.Up}
+	for _, client := range clients {
+		player := client.Welcome().Player
+		if err := client.SendTurn(turns[player]); err != nil {
+			t.Fatalf("player %d: SendTurn: %v", player, err)
+		}
+	}
+
+	res := <-finished
+	if res.err != nil {
+		t.Fatalf("Run: %v", res.err)
+	}
+	if res.rep == nil {
+		t.Fatal("Run returned no replay")
+	}
+
+	// после конца партии сервер закрывает соединения; к этому моменту у клиента уже последний снимок
+	states := make([]State, players)
+	for i, client := range clients {
+		for client.Err() == nil {
+			select {
+			case <-ctx.Done():
+				t.Fatalf("client %d: connection was not closed after the game", i)
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+		state, ok := client.State()
+		if !ok {
+			t.Fatalf("client %d received no state", i)
+		}
+		states[i] = state
+	}
+
+	if !states[0].Over {
+		t.Fatalf("final state is not over: %+v", states[0])
+	}
+	for i := 1; i < players; i++ {
+		if !reflect.DeepEqual(states[i], states[0]) {
+			t.Fatalf("client %d final state differs:\n%+v\nwant\n%+v", i, states[i], states[0])
+		}
+	}
+	if states[0].Tick != res.rep.Ticks {
+		t.Fatalf("final state tick %d, replay ticks %d", states[0].Tick, res.rep.Ticks)
+	}
+}
+
+func TestServerBufferDropsDistantInputs(t *testing.T) {
+	server := newTestServer(t, 1)
+	const tick = 100
+	limit := tick + server.cfg.InputDelay + maxInputLead
+
+	var buffer []Input
+	buffer = server.buffer(buffer, peerInput{input: Input{Tick: limit, Direction: core.Up}}, tick)
+	buffer = server.buffer(buffer, peerInput{input: Input{Tick: limit + 1, Direction: core.Left}}, tick)
+	if len(buffer) != 1 || buffer[0].Tick != limit {
+		t.Fatalf("buffer = %+v, want only the input for tick %d", buffer, limit)
+	}
+
+	for range 2 * maxPendingInputs {
+		buffer = server.buffer(buffer, peerInput{input: Input{Tick: tick, Direction: core.Up}}, tick)
+	}
+	if len(buffer) != maxPendingInputs {
+		t.Fatalf("buffer holds %d inputs, want at most %d", len(buffer), maxPendingInputs)
+	}
+}
@@ -0,0 +1,160 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"snake-game/internal/core"
+	"snake-game/internal/sim"
+	"strings"
+	"time"
+)
+
+const (
+	CurrentVersion = 1
+	Dir            = "replays"
+)
+
+// Turn — нажатие направления игроком на конкретном тике.
+type Turn struct {
+	Tick      int            `json:"tick"`
+	Player    int            `json:"player,omitempty"`
+	Direction core.Direction `json:"direction"`
+	// Replace — поворот бота, который заменил запланированные повороты (см. sim.Input)
+	Replace bool `json:"replace,omitempty"`
+}
+
+// Replay содержит всё, что нужно для покадрового воспроизведения игры.
+type Replay struct {
+	Version   int        `json:"version"`
+	Seed      uint64     `json:"seed"`
+	Level     core.Level `json:"level"`
+	Rules     sim.Rules  `json:"rules"`
+	Mode      string     `json:"mode,omitempty"`
+	Players   int        `json:"players,omitempty"`
+	Turns     []Turn     `json:"turns"`
+	Score     int        `json:"score"`
+	Scores    []int      `json:"scores,omitempty"`
+	Winner    int        `json:"winner,omitempty"`
+	Ticks     int        `json:"ticks"`
+	Stopped   bool       `json:"stopped,omitempty"` // игрок сам завершил игру на последнем тике
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func New(level *core.Level, rules sim.Rules, mode sim.GameMode, players int, seed uint64) *Replay {
+	return &Replay{
+		Version:   CurrentVersion,
+		Seed:      seed,
+		Level:     *level,
+		Rules:     rules,
+		Mode:      mode.Name(),
+		Players:   players,
+		Turns:     make([]Turn, 0),
+		CreatedAt: time.Now(),
+	}
+}
+
+func (r *Replay) Record(tick, player int, direction core.Direction) {
+	r.Turns = append(r.Turns, Turn{Tick: tick, Player: player, Direction: direction})
+}
+
+// RecordInput записывает все повороты из ввода игрока вместе с признаком Replace.
+func (r *Replay) RecordInput(tick, player int, input sim.Input) {
+	for _, direction := range input.Turns {
+		r.Turns = append(r.Turns, Turn{Tick: tick, Player: player, Direction: direction, Replace: input.Replace})
+	}
+}
+
+// PlayersCount учитывает повторы, записанные до появления игры вдвоём.
+func (r *Replay) PlayersCount() int {
+	return max(r.Players, 1)
+}
+
+func (r *Replay) Finish(world *sim.World) {
+	r.Score = world.Scores[0]
+	r.Scores = append([]int(nil), world.Scores...)
+	r.Winner = world.Winner()
+	r.Ticks = world.Tick
+	r.Stopped = world.DeathCause == sim.Stopped
+}
+
+func (r *Replay) NewWorld() (*sim.World, error) {
+	mode, err := sim.ModeByName(r.Mode)
+	if err != nil {
+		return nil, err
+	}
+	return sim.NewWorld(&r.Level, r.Rules, mode, r.PlayersCount(), r.Seed)
+}
+
+// Save записывает повтор в каталог dir и возвращает путь к файлу.
+func (r *Replay) Save(dir string) (string, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal replay: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create replays directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s_%s_%d.json", sanitize(r.Level.Name), r.CreatedAt.Format("20060102-150405"), r.Seed%100000)
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write replay file: %w", err)
+	}
+	return path, nil
+}
+
+func Load(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay file: %w", err)
+	}
+
+	var r Replay
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse replay file: %w", err)
+	}
+	if r.Version > CurrentVersion {
+		return nil, fmt.Errorf("unsupported replay version %d", r.Version)
+	}
+	return &r, nil
+}
+
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// Player выдаёт записанный ввод тик за тиком.
+type Player struct {
+	replay *Replay
+	next   int
+}
+
+func NewPlayer(r *Replay) *Player {
+	return &Player{replay: r}
+}
+
+// Inputs возвращает ввод всех игроков для тика tick.
+func (p *Player) Inputs(tick int) []sim.Input {
+	inputs := make([]sim.Input, p.replay.PlayersCount())
+	turns := p.replay.Turns
+	for p.next < len(turns) && turns[p.next].Tick <= tick {
+		turn := turns[p.next]
+		if turn.Tick == tick && turn.Player >= 0 && turn.Player < len(inputs) {
+			inputs[turn.Player].Turns = append(inputs[turn.Player].Turns, turn.Direction)
+			inputs[turn.Player].Replace = inputs[turn.Player].Replace || turn.Replace
+		}
+		p.next++
+	}
+	if p.replay.Stopped && tick == p.replay.Ticks-1 {
+		inputs[0].Finish = true
+	}
+	return inputs
+}
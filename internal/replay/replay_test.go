@@ -0,0 +1,77 @@
+package replay
+
+import (
+	"reflect"
+	"snake-game/internal/ai"
+	"snake-game/internal/core"
+	"snake-game/internal/sim"
+	"testing"
+)
+
+// TestReplayRoundTrip записывает партию двух ботов с зерном, сохраняет повтор на диск
+// и проверяет, что воспроизведение приходит к тем же счёту и тику.
+func TestReplayRoundTrip(t *testing.T) {
+	const maxTicks = 20000
+	level := core.NewLevel("round-trip", 16, 9, []core.Wall{
+		{Position: core.Position{X: 4, Y: 1}},
+		{Position: core.Position{X: 11, Y: 7}},
+	})
+	rules := sim.Rules{
+		InitialSnakeLen:       3,
+		InitialSpeed:          6,
+		SpeedIncreaseInterval: 3,
+		SpeedIncreaseAmount:   1,
+		MaxSpeed:              2,
+		TurnQueue:             sim.TurnQueueSize,
+	}
+	bots := []ai.Controller{ai.Greedy{}, ai.BFS{}}
+
+	w, err := sim.NewWorld(level, rules, sim.ClassicMode{}, len(bots), 42)
+	if err != nil {
+		t.Fatalf("NewWorld: %v", err)
+	}
+	rep := New(level, rules, sim.ClassicMode{}, len(bots), 42)
+	for !w.IsOver() && w.Tick < maxTicks {
+		inputs := make([]sim.Input, len(bots))
+		for player, bot := range bots {
+			inputs[player] = ai.Input(bot, w, player)
+			rep.RecordInput(w.Tick, player, inputs[player])
+		}
+		w.Step(inputs...)
+	}
+	if !w.IsOver() {
+		t.Fatalf("game is not over after %d ticks", maxTicks)
+	}
+	if w.Scores[0]+w.Scores[1] == 0 {
+		t.Fatal("bots scored nothing: the game is too short to check the replay")
+	}
+	rep.Finish(w)
+
+	path, err := rep.Save(t.TempDir())
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	replayed, err := loaded.NewWorld()
+	if err != nil {
+		t.Fatalf("NewWorld from replay: %v", err)
+	}
+	player := NewPlayer(loaded)
+	for !replayed.IsOver() && replayed.Tick < maxTicks {
+		replayed.Step(player.Inputs(replayed.Tick)...)
+	}
+
+	if replayed.Tick != w.Tick || replayed.Tick != loaded.Ticks {
+		t.Fatalf("replay ended on tick %d, game on tick %d, recorded %d", replayed.Tick, w.Tick, loaded.Ticks)
+	}
+	if !reflect.DeepEqual(replayed.Scores, w.Scores) || !reflect.DeepEqual(replayed.Scores, loaded.Scores) {
+		t.Fatalf("replay scores %v, game scores %v, recorded %v", replayed.Scores, w.Scores, loaded.Scores)
+	}
+	if replayed.DeathCause != w.DeathCause {
+		t.Fatalf("replay ended with %s, game with %s", replayed.DeathCause, w.DeathCause)
+	}
+}
@@ -0,0 +1,348 @@
+package scenes
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"image"
+	"image/color"
+	"snake-game/internal/campaign"
+	"snake-game/internal/config"
+	"snake-game/internal/core"
+	"snake-game/internal/levels"
+	"snake-game/internal/replay"
+	"snake-game/internal/ui"
+	"strings"
+	"time"
+)
+
+const (
+	campaignHeaderY   = 260
+	campaignRowHeight = 35
+)
+
+var (
+	lockedColor    = color.Gray{Y: 100}
+	completedColor = color.RGBA{R: 120, G: 220, B: 120, A: 255}
+	problemColor   = color.RGBA{R: 255, G: 100, B: 100, A: 255}
+)
+
+// CampaignScene — выбор набора уровней и уровня в нём. Уровни открываются по очереди:
+// следующий — после того как игрок набрал цель на предыдущем.
+type CampaignScene struct {
+	accessor GameAccessor
+
+	packs        []*campaign.Pack
+	currentPack  int
+	currentStage int
+	progress     *campaign.Progress
+	loadError    error
+	// status — почему нельзя начать выбранный уровень
+	status string
+
+	playerName    []rune
+	nameFieldRect image.Rectangle
+	cursorVisible bool
+	cursorBlink   time.Time
+
+	nextState  core.GameState
+	playButton *ui.Button
+	backButton *ui.Button
+}
+
+func NewCampaignScene(accessor GameAccessor) *CampaignScene {
+	scene := &CampaignScene{
+		accessor:  accessor,
+		nextState: core.CampaignState,
+	}
+
+	scene.Relayout()
+	return scene
+}
+
+// Relayout ставит поле имени по центру сверху, а кнопки — по центру у нижнего края экрана.
+func (s *CampaignScene) Relayout() {
+	layout := s.accessor.Screen()
+	centerX := layout.Width / 2
+	s.nameFieldRect = image.Rect(centerX-20, 110, centerX+220, 150)
+
+	buttonY := float64(layout.Height - 110)
+	s.playButton = ui.NewButton(float64(centerX)-250, buttonY, 240, 50, "PLAY", s.play)
+	s.backButton = ui.NewButton(float64(centerX)+10, buttonY, 240, 50, "BACK", func() {
+		s.nextState = core.MainMenuState
+	})
+}
+
+func (s *CampaignScene) OnEnter() {
+	s.accessor.Logger().Info("entering campaign scene")
+	s.nextState = core.CampaignState
+	s.status = ""
+
+	progress, err := campaign.LoadProgress(s.accessor.Config().CampaignProgressFile)
+	if err != nil {
+		s.accessor.Logger().Error("failed to load campaign progress", "error", err)
+		s.loadError = err
+		return
+	}
+	s.progress = progress
+	if len(s.playerName) == 0 {
+		s.playerName = []rune(progress.LastPlayer())
+	}
+
+	packs, err := campaign.ScanPacks(campaign.Dir)
+	if err != nil {
+		s.accessor.Logger().Warn("failed to load some campaigns", "error", err)
+	}
+	s.packs = packs
+	s.loadError = nil
+	if len(packs) == 0 {
+		s.loadError = fmt.Errorf("no campaigns found in %s", campaign.Dir)
+		return
+	}
+
+	// выбор сохраняется между заходами, но наборы могли измениться на диске
+	s.currentPack = min(s.currentPack, len(packs)-1)
+	s.currentStage = min(s.currentStage, len(packs[s.currentPack].Stages)-1)
+}
+
+func (s *CampaignScene) player() string {
+	return strings.TrimSpace(string(s.playerName))
+}
+
+func (s *CampaignScene) pack() *campaign.Pack {
+	return s.packs[s.currentPack]
+}
+
+func (s *CampaignScene) play() {
+	if s.loadError != nil {
+		return
+	}
+	player := s.player()
+	if player == "" {
+		s.status = "ENTER YOUR NAME"
+		return
+	}
+
+	pack := s.pack()
+	stageIndex := s.currentStage
+	stage := pack.Stages[stageIndex]
+	if !stage.IsValid() {
+		s.status = "INVALID LEVEL: " + levels.Reason(stage.Err)
+		return
+	}
+	if !pack.IsUnlocked(s.progress, player, stageIndex) {
+		s.status = "LEVEL IS LOCKED"
+		return
+	}
+
+	s.accessor.Logger().Info("starting campaign level", "pack", pack.ID, "level", stage.ID(), "player", player)
+	s.nextState = core.GamePlayingState
+	s.accessor.StartGame(GameSetup{
+		Level:       stage.Level,
+		Mode:        pack.Mode,
+		Players:     1,
+		TargetScore: stage.TargetScore,
+		PlayerName:  player,
+		ReturnState: core.CampaignState,
+		OnFinish: func(rep *replay.Replay) {
+			s.recordResult(player, pack, stageIndex, rep.Scores[0])
+		},
+	})
+}
+
+// recordResult сохраняет результат уровня и, если уровень пройден впервые, выбирает следующий.
+func (s *CampaignScene) recordResult(player string, pack *campaign.Pack, stage, score int) {
+	unlocked := s.progress.Record(player, pack, stage, score)
+	if err := s.progress.Save(); err != nil {
+		s.accessor.Logger().Error("failed to save campaign progress", "error", err)
+	}
+	if unlocked && stage+1 < len(pack.Stages) {
+		s.accessor.Logger().Info("next campaign level unlocked", "pack", pack.ID, "level", pack.Stages[stage+1].ID(), "player", player)
+		if s.pack() == pack {
+			s.currentStage = stage + 1
+		}
+	}
+}
+
+func (s *CampaignScene) Update() (core.GameState, error) {
+	s.handleInput()
+	s.playButton.Update()
+	s.backButton.Update()
+
+	if time.Since(s.cursorBlink) > time.Millisecond*500 {
+		s.cursorVisible = !s.cursorVisible
+		s.cursorBlink = time.Now()
+	}
+	return s.nextState, nil
+}
+
+func (s *CampaignScene) handleInput() {
+	controls := s.accessor.Input()
+	if controls.JustPressed(config.ActionBack) {
+		s.nextState = core.MainMenuState
+		return
+	}
+
+	name := ebiten.AppendInputChars(s.playerName)
+	if len(name) > MaxPlayerName {
+		name = name[0:MaxPlayerName]
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(name) > 0 {
+		name = name[0 : len(name)-1]
+	}
+	s.playerName = name
+
+	if s.loadError != nil {
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) {
+		s.selectPack((s.currentPack + len(s.packs) - 1) % len(s.packs))
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) {
+		s.selectPack((s.currentPack + 1) % len(s.packs))
+	}
+
+	stages := len(s.pack().Stages)
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
+		s.currentStage = (s.currentStage + stages - 1) % stages
+		s.status = ""
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
+		s.currentStage = (s.currentStage + 1) % stages
+		s.status = ""
+	} else if controls.JustPressed(config.ActionConfirm) {
+		s.play()
+	}
+
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		_, cursorY := ebiten.CursorPosition()
+		row := (cursorY - campaignHeaderY + campaignRowHeight/2) / campaignRowHeight
+		if cursorY > campaignHeaderY && row >= 1 && row <= stages {
+			s.currentStage = row - 1
+			s.status = ""
+		}
+	}
+}
+
+func (s *CampaignScene) selectPack(pack int) {
+	s.currentPack = pack
+	s.currentStage = 0
+	s.status = ""
+}
+
+func (s *CampaignScene) Draw(screen *ebiten.Image) {
+	layout := s.accessor.Screen()
+	assets := s.accessor.Assets()
+	uiFont := assets.UIFont
+
+	screen.Fill(color.RGBA{R: 20, G: 20, B: 40, A: 255})
+
+	title := "CAMPAIGN"
+	titleBounds := text.BoundString(assets.TitleFont, title)
+	text.Draw(screen, title, assets.TitleFont, (layout.Width-titleBounds.Dx())/2, 60, color.White)
+
+	s.drawNameField(screen)
+
+	if s.loadError != nil {
+		errorMsg := "Error: " + s.loadError.Error()
+		errorBounds := text.BoundString(uiFont, errorMsg)
+		text.Draw(screen, errorMsg, uiFont, (layout.Width-errorBounds.Dx())/2, layout.Height/2, problemColor)
+		s.backButton.Draw(screen, assets)
+		return
+	}
+
+	pack := s.pack()
+	packText := fmt.Sprintf("< %s >  %d/%d", pack.Name, pack.CompletedCount(s.progress, s.player()), len(pack.Stages))
+	packBounds := text.BoundString(uiFont, packText)
+	text.Draw(screen, packText, uiFont, (layout.Width-packBounds.Dx())/2, 190, color.White)
+	if pack.Description != "" {
+		descriptionBounds := text.BoundString(uiFont, pack.Description)
+		text.Draw(screen, pack.Description, uiFont, (layout.Width-descriptionBounds.Dx())/2, 220, color.Gray{Y: 180})
+	}
+
+	s.drawStages(screen, pack)
+
+	if s.status != "" {
+		statusBounds := text.BoundString(uiFont, s.status)
+		text.Draw(screen, s.status, uiFont, (layout.Width-statusBounds.Dx())/2, layout.Height-130, problemColor)
+	}
+
+	s.playButton.Draw(screen, assets)
+	s.backButton.Draw(screen, assets)
+
+	controls := s.accessor.Input()
+	hint := "LEFT/RIGHT - campaign, UP/DOWN - level, " + keyHint(controls, config.ActionConfirm) + " - play, " + keyHint(controls, config.ActionBack) + " - back"
+	hintBounds := text.BoundString(uiFont, hint)
+	text.Draw(screen, hint, uiFont, (layout.Width-hintBounds.Dx())/2, layout.Height-20, color.Gray{Y: 180})
+}
+
+func (s *CampaignScene) drawNameField(screen *ebiten.Image) {
+	assets := s.accessor.Assets()
+	rect := s.nameFieldRect
+
+	label := "Player Name:"
+	labelBounds := text.BoundString(assets.UIFont, label)
+	text.Draw(screen, label, assets.UIFont, rect.Min.X-10-labelBounds.Dx(), rect.Min.Y+28, color.White)
+
+	ui.DrawRectangle(screen, assets, float64(rect.Min.X-2), float64(rect.Min.Y-2), float64(rect.Dx()+4), float64(rect.Dy()+4), color.Gray{Y: 128})
+	ui.DrawRectangle(screen, assets, float64(rect.Min.X), float64(rect.Min.Y), float64(rect.Dx()), float64(rect.Dy()), color.Black)
+	name := string(s.playerName)
+	text.Draw(screen, name, assets.UIFont, rect.Min.X+5, rect.Min.Y+28, color.White)
+
+	if s.cursorVisible {
+		nameBounds := text.BoundString(assets.UIFont, name)
+		cursorX := float64(rect.Min.X+5) + float64(nameBounds.Dx()) + 2
+		ui.DrawRectangle(screen, assets, cursorX, float64(rect.Min.Y+8), 2, float64(rect.Dy()-16), color.White)
+	}
+}
+
+func (s *CampaignScene) drawStages(screen *ebiten.Image, pack *campaign.Pack) {
+	assets := s.accessor.Assets()
+	uiFont := assets.UIFont
+	player := s.player()
+
+	colX_Num := s.accessor.Screen().Width/2 - 500
+	colX_Level := colX_Num + 60
+	colX_Target := colX_Num + 460
+	colX_Best := colX_Num + 600
+	colX_Status := colX_Num + 720
+
+	text.Draw(screen, "№", uiFont, colX_Num, campaignHeaderY, color.White)
+	text.Draw(screen, "LEVEL", uiFont, colX_Level, campaignHeaderY, color.White)
+	text.Draw(screen, "TARGET", uiFont, colX_Target, campaignHeaderY, color.White)
+	text.Draw(screen, "BEST", uiFont, colX_Best, campaignHeaderY, color.White)
+	text.Draw(screen, "STATUS", uiFont, colX_Status, campaignHeaderY, color.White)
+
+	for i, stage := range pack.Stages {
+		rowY := campaignHeaderY + (i+1)*campaignRowHeight
+
+		var rowColor color.Color = color.White
+		status := "OPEN"
+		switch {
+		case !stage.IsValid():
+			rowColor, status = problemColor, "INVALID"
+		case pack.IsCompleted(s.progress, player, i):
+			rowColor, status = completedColor, "COMPLETE"
+		case !pack.IsUnlocked(s.progress, player, i):
+			rowColor, status = lockedColor, "LOCKED"
+		}
+
+		if i == s.currentStage {
+			ui.DrawRectangle(screen, assets, float64(colX_Num-10), float64(rowY-campaignRowHeight+8), float64(colX_Status-colX_Num+180), campaignRowHeight, color.RGBA{R: 60, G: 60, B: 100, A: 255})
+		}
+
+		name := stage.ID()
+		if stage.Level != nil && stage.Level.Name != "" {
+			name = stage.Level.Name
+		}
+		best := "-"
+		if score, ok := s.progress.Best(player, pack.ID, stage.ID()); ok {
+			best = fmt.Sprintf("%d", score)
+		}
+
+		text.Draw(screen, fmt.Sprintf("%d.", i+1), uiFont, colX_Num, rowY, rowColor)
+		text.Draw(screen, name, uiFont, colX_Level, rowY, rowColor)
+		text.Draw(screen, fmt.Sprintf("%d", stage.TargetScore), uiFont, colX_Target, rowY, rowColor)
+		text.Draw(screen, best, uiFont, colX_Best, rowY, rowColor)
+		text.Draw(screen, status, uiFont, colX_Status, rowY, rowColor)
+	}
+}
@@ -0,0 +1,323 @@
+package scenes
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"image/color"
+	"math/rand/v2"
+	"path/filepath"
+	"snake-game/internal/levelgen"
+	"snake-game/internal/levels"
+	"snake-game/internal/ui"
+	"strconv"
+)
+
+// editorDialog — модальное окно редактора поверх поля.
+type editorDialog int
+
+const (
+	noDialog editorDialog = iota
+	// openDialog — список уровней из levels/ для открытия
+	openDialog
+	// overwriteDialog — подтверждение перезаписи открытого файла
+	overwriteDialog
+	// generateDialog — параметры генератора уровней
+	generateDialog
+)
+
+const (
+	// generatorDensityStep — шаг изменения плотности стен кнопками +/-
+	generatorDensityStep = 0.05
+	// generatorDensity — плотность стен генератора по умолчанию
+	generatorDensity = 0.2
+
+	openDialogRows      = 15
+	openDialogRowHeight = 36
+	openDialogWidth     = 700
+)
+
+// Relayout раскладывает кнопки диалогов по центру экрана. Кнопки верхней панели привязаны
+// к левому краю и от размера экрана не зависят.
+func (c *CreateLevelScene) Relayout() {
+	layout := c.accessor.Screen()
+	centerX := float64(layout.Width) / 2
+	buttonY := float64(layout.Height)/2 + 20
+
+	c.overwriteButton = ui.NewButton(centerX-380, buttonY, 240, 50, "OVERWRITE", func() {
+		c.saveTo(c.path)
+	})
+	c.copyButton = ui.NewButton(centerX-120, buttonY, 240, 50, "SAVE AS COPY", c.saveCopy)
+	c.cancelButton = ui.NewButton(centerX+140, buttonY, 240, 50, "CANCEL", c.closeDialog)
+
+	optionsY := float64(layout.Height)/2 - 140
+	c.genStyleButton = ui.NewButton(centerX-120, optionsY, 240, 50, "", func() {
+		c.genStyle = (c.genStyle + 1) % len(levelgen.Styles())
+	})
+	c.genDensityMinus = ui.NewButton(centerX-120, optionsY+60, 50, 50, "-", func() {
+		c.genDensity = max(c.genDensity-generatorDensityStep, 0)
+	})
+	c.genDensityPlus = ui.NewButton(centerX+70, optionsY+60, 50, 50, "+", func() {
+		c.genDensity = min(c.genDensity+generatorDensityStep, 1)
+	})
+	c.genSymmetricButton = ui.NewButton(centerX-120, optionsY+120, 240, 50, "", func() {
+		c.genSymmetric = !c.genSymmetric
+	})
+	c.generateButton = ui.NewButton(centerX-380, buttonY+60, 240, 50, "GENERATE", c.generate)
+	c.genCancelButton = ui.NewButton(centerX+140, buttonY+60, 240, 50, "CANCEL", c.closeDialog)
+}
+
+// generate заменяет стены редактора уровнем из генератора со случайным зерном.
+// Замена отменяется как обычная правка; старты из сгенерированного уровня переходят в редактор.
+func (c *CreateLevelScene) generate() {
+	c.dialog = noDialog
+	if !c.isWidthValid || !c.isHeightValid {
+		c.accessor.Logger().Warn("Generate aborted: invalid size")
+		return
+	}
+
+	params := levelgen.Params{
+		Seed:      rand.Uint64(),
+		Width:     c.width,
+		Height:    c.height,
+		Density:   c.genDensity,
+		Style:     levelgen.Styles()[c.genStyle],
+		Symmetric: c.genSymmetric,
+		Name:      string(c.LevelName),
+	}
+	level, err := levelgen.Generate(params)
+	if err != nil {
+		c.accessor.Logger().Warn("failed to generate level", "error", err)
+		c.status = "CAN'T GENERATE: " + err.Error()
+		return
+	}
+	c.accessor.Logger().Info("level generated", "style", params.Style, "seed", params.Seed, "density", params.Density, "symmetric", params.Symmetric)
+
+	c.canvas.Replace(level.Walls)
+	if c.source == nil {
+		c.source = level
+	} else {
+		source := *c.source
+		source.Spawns = level.Spawns
+		c.source = &source
+	}
+	c.status = ""
+}
+
+// showOpenDialog перечитывает каталог уровней и показывает список для открытия.
+func (c *CreateLevelScene) showOpenDialog() {
+	entries, err := levels.Scan(levels.Dir)
+	if err != nil {
+		c.accessor.Logger().Error("failed to scan for levels", "error", err)
+	}
+	c.openEntries = entries
+	c.openSelected = 0
+	c.openOffset = 0
+	c.dialog = openDialog
+}
+
+func (c *CreateLevelScene) closeDialog() {
+	c.dialog = noDialog
+}
+
+// openEntry загружает уровень в редактор. Неисправный уровень тоже можно открыть,
+// чтобы исправить его; нельзя открыть только файл, который не разобрался.
+func (c *CreateLevelScene) openEntry(entry levels.Entry) {
+	if entry.Level == nil {
+		c.accessor.Logger().Warn("can't open level", "path", entry.Path, "error", entry.Err)
+		c.status = "CAN'T OPEN: " + levels.Reason(entry.Err)
+		c.dialog = noDialog
+		return
+	}
+	c.accessor.Logger().Info("opening level in editor", "path", entry.Path)
+
+	level := entry.Level
+	c.source = level
+	c.path = entry.Path
+	c.LevelName = []rune(level.Name)
+	c.width, c.height = level.GridWidth, level.GridHeight
+	c.widthStr = []rune(strconv.Itoa(level.GridWidth))
+	c.heightStr = []rune(strconv.Itoa(level.GridHeight))
+	c.canvas.Load(level.GridWidth, level.GridHeight, level.Walls)
+	c.dragging = false
+	c.activeField = ""
+	c.status = ""
+	if entry.Err != nil {
+		c.status = "INVALID LEVEL: " + levels.Reason(entry.Err)
+	}
+	c.validateInputs()
+	c.dialog = noDialog
+}
+
+func (c *CreateLevelScene) updateDialog() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		c.closeDialog()
+		return
+	}
+
+	switch c.dialog {
+	case openDialog:
+		c.updateOpenDialog()
+	case overwriteDialog:
+		c.overwriteButton.Update()
+		c.copyButton.Update()
+		c.cancelButton.Update()
+	case generateDialog:
+		c.genStyleButton.Update()
+		c.genDensityMinus.Update()
+		c.genDensityPlus.Update()
+		c.genSymmetricButton.Update()
+		c.generateButton.Update()
+		c.genCancelButton.Update()
+	}
+}
+
+func (c *CreateLevelScene) updateOpenDialog() {
+	if len(c.openEntries) == 0 {
+		if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+			c.closeDialog()
+		}
+		return
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
+		c.openSelected = (c.openSelected + len(c.openEntries) - 1) % len(c.openEntries)
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
+		c.openSelected = (c.openSelected + 1) % len(c.openEntries)
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		c.openEntry(c.openEntries[c.openSelected])
+		return
+	}
+	_, wheelY := ebiten.Wheel()
+	if wheelY > 0 {
+		c.openSelected = max(c.openSelected-1, 0)
+	} else if wheelY < 0 {
+		c.openSelected = min(c.openSelected+1, len(c.openEntries)-1)
+	}
+
+	// список прокручивается так, чтобы выбранный уровень был виден
+	if c.openSelected < c.openOffset {
+		c.openOffset = c.openSelected
+	} else if c.openSelected >= c.openOffset+openDialogRows {
+		c.openOffset = c.openSelected - openDialogRows + 1
+	}
+
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		cursorX, cursorY := ebiten.CursorPosition()
+		left, top := c.openDialogOrigin()
+		row := (cursorY - top) / openDialogRowHeight
+		if cursorX >= left && cursorX < left+openDialogWidth && cursorY >= top && row < openDialogRows {
+			if index := c.openOffset + row; index < len(c.openEntries) {
+				c.openEntry(c.openEntries[index])
+			}
+		}
+	}
+}
+
+// openDialogOrigin — левый верхний угол первой строки списка уровней.
+func (c *CreateLevelScene) openDialogOrigin() (int, int) {
+	layout := c.accessor.Screen()
+	return (layout.Width - openDialogWidth) / 2, layout.TopBarHeight + 140
+}
+
+func (c *CreateLevelScene) drawDialog(screen *ebiten.Image) {
+	layout := c.accessor.Screen()
+	assets := c.accessor.Assets()
+
+	opOverlay := &ebiten.DrawImageOptions{}
+	opOverlay.GeoM.Scale(float64(layout.Width), float64(layout.Height))
+	opOverlay.ColorScale.Scale(0, 0, 0, 0.8)
+	screen.DrawImage(assets.WhitePixel, opOverlay)
+
+	switch c.dialog {
+	case openDialog:
+		c.drawOpenDialog(screen)
+	case overwriteDialog:
+		question := fmt.Sprintf("OVERWRITE %s?", filepath.Base(c.path))
+		questionBounds := text.BoundString(assets.UIFont, question)
+		text.Draw(screen, question, assets.UIFont, (layout.Width-questionBounds.Dx())/2, layout.Height/2-30, color.White)
+
+		c.overwriteButton.Draw(screen, assets)
+		c.copyButton.Draw(screen, assets)
+		c.cancelButton.Draw(screen, assets)
+	case generateDialog:
+		c.drawGenerateDialog(screen)
+	}
+}
+
+func (c *CreateLevelScene) drawGenerateDialog(screen *ebiten.Image) {
+	layout := c.accessor.Screen()
+	assets := c.accessor.Assets()
+
+	title := "GENERATE LEVEL"
+	titleBounds := text.BoundString(assets.TitleFont, title)
+	text.Draw(screen, title, assets.TitleFont, (layout.Width-titleBounds.Dx())/2, int(c.genStyleButton.Y)-40, color.White)
+
+	c.genStyleButton.Text = "Style: " + levelgen.Styles()[c.genStyle]
+	c.genStyleButton.Draw(screen, assets)
+
+	density := fmt.Sprintf("Density: %.2f", c.genDensity)
+	densityBounds := text.BoundString(assets.UIFont, density)
+	text.Draw(screen, density, assets.UIFont, (layout.Width-densityBounds.Dx())/2, int(c.genDensityMinus.Y)+32, color.White)
+	c.genDensityMinus.Draw(screen, assets)
+	c.genDensityPlus.Draw(screen, assets)
+
+	c.genSymmetricButton.Text = "Symmetric: off"
+	if c.genSymmetric {
+		c.genSymmetricButton.Text = "Symmetric: on"
+	}
+	c.genSymmetricButton.Draw(screen, assets)
+
+	size := fmt.Sprintf("Size: %dx%d (W and H fields)", c.width, c.height)
+	sizeBounds := text.BoundString(assets.UIFont, size)
+	text.Draw(screen, size, assets.UIFont, (layout.Width-sizeBounds.Dx())/2, int(c.genSymmetricButton.Y)+90, color.Gray{Y: 180})
+
+	c.generateButton.Draw(screen, assets)
+	c.genCancelButton.Draw(screen, assets)
+}
+
+func (c *CreateLevelScene) drawOpenDialog(screen *ebiten.Image) {
+	layout := c.accessor.Screen()
+	assets := c.accessor.Assets()
+	uiFont := assets.UIFont
+	left, top := c.openDialogOrigin()
+
+	title := "OPEN LEVEL"
+	titleBounds := text.BoundString(assets.TitleFont, title)
+	text.Draw(screen, title, assets.TitleFont, (layout.Width-titleBounds.Dx())/2, top-50, color.White)
+
+	if len(c.openEntries) == 0 {
+		message := "No levels found in " + levels.Dir
+		messageBounds := text.BoundString(uiFont, message)
+		text.Draw(screen, message, uiFont, (layout.Width-messageBounds.Dx())/2, top+openDialogRowHeight, color.Gray{Y: 180})
+		return
+	}
+
+	last := min(c.openOffset+openDialogRows, len(c.openEntries))
+	for index := c.openOffset; index < last; index++ {
+		entry := c.openEntries[index]
+		rowY := top + (index-c.openOffset)*openDialogRowHeight
+		if index == c.openSelected {
+			ui.DrawRectangle(screen, assets, float64(left), float64(rowY), openDialogWidth, openDialogRowHeight, color.RGBA{R: 60, G: 60, B: 100, A: 255})
+		}
+
+		var rowColor color.Color = color.White
+		label := entry.Name()
+		switch {
+		case entry.Level == nil:
+			rowColor = color.Gray{Y: 100}
+			label += "  (" + levels.Reason(entry.Err) + ")"
+		case !entry.IsValid():
+			rowColor = color.RGBA{R: 255, G: 100, B: 100, A: 255}
+			label += "  (" + levels.Reason(entry.Err) + ")"
+		default:
+			label += fmt.Sprintf("  %dx%d", entry.Level.GridWidth, entry.Level.GridHeight)
+		}
+		text.Draw(screen, label, uiFont, left+10, rowY+26, rowColor)
+	}
+
+	hint := "UP/DOWN - select, ENTER - open, ESC - cancel"
+	hintBounds := text.BoundString(uiFont, hint)
+	text.Draw(screen, hint, uiFont, (layout.Width-hintBounds.Dx())/2, top+(openDialogRows+1)*openDialogRowHeight, color.Gray{Y: 180})
+}
@@ -1,7 +1,6 @@
 package scenes
 
 import (
-	"encoding/json"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text"
@@ -10,6 +9,8 @@ import (
 	"os"
 	"path/filepath"
 	"snake-game/internal/core"
+	"snake-game/internal/editor"
+	"snake-game/internal/levels"
 	"snake-game/internal/ui"
 	"strconv"
 	"strings"
@@ -25,10 +26,22 @@ const (
 type CreateLevelScene struct {
 	accessor GameAccessor
 
-	walls  map[core.Position]bool
+	canvas *editor.Canvas
 	width  int
 	height int
 
+	// source — открытый уровень: его поля, которых нет в редакторе (старты, еда, скорость), сохраняются как есть
+	source *core.Level
+	// path — файл открытого уровня; пусто для нового уровня
+	path string
+
+	tool editor.Tool
+	// drag — текущее рисование мышью: с какой клетки начато, где курсор и ставятся стены или стираются
+	dragging  bool
+	dragWall  bool
+	dragStart core.Position
+	dragEnd   core.Position
+
 	maximalWidth  int
 	maximalHeight int
 
@@ -40,13 +53,43 @@ type CreateLevelScene struct {
 	isWidthValid  bool
 	isHeightValid bool
 
-	saveButton  *ui.Button
-	resetButton *ui.Button
+	saveButton   *ui.Button
+	resetButton  *ui.Button
+	openButton   *ui.Button
+	testButton   *ui.Button
+	genButton    *ui.Button
+	toolButtons  []*ui.Button
+	mirrorButton *ui.Button
+	undoButton   *ui.Button
+	redoButton   *ui.Button
+
+	dialog          editorDialog
+	openEntries     []levels.Entry
+	openSelected    int
+	openOffset      int
+	overwriteButton *ui.Button
+	copyButton      *ui.Button
+	cancelButton    *ui.Button
+
+	genStyle           int
+	genDensity         float64
+	genSymmetric       bool
+	genStyleButton     *ui.Button
+	genDensityMinus    *ui.Button
+	genDensityPlus     *ui.Button
+	genSymmetricButton *ui.Button
+	generateButton     *ui.Button
+	genCancelButton    *ui.Button
 
 	nameFieldRect   image.Rectangle
 	widthFieldRect  image.Rectangle
 	heightFieldRect image.Rectangle
 
+	// status — почему не удалось сохранить, открыть или испытать уровень
+	status string
+	// testing — идёт пробная игра; по возвращении в редактор правки не сбрасываются
+	testing bool
+
 	activeField   string
 	cursorVisible bool
 	cursorBlink   time.Time
@@ -59,7 +102,9 @@ func NewCreateLevelScene(accessor GameAccessor) *CreateLevelScene {
 		accessor: accessor,
 	}
 
+	scene.genDensity = generatorDensity
 	scene.layoutUI()
+	scene.Relayout()
 	scene.reset()
 
 	return scene
@@ -79,7 +124,21 @@ func (c *CreateLevelScene) layoutUI() {
 
 	saveButtonWidth := 100.0
 	c.saveButton = ui.NewButton(currentX, centerY-20, saveButtonWidth, 40, "Save", c.save)
-	currentX += saveButtonWidth + 40
+	currentX += saveButtonWidth + 20
+
+	openButtonWidth := 100.0
+	c.openButton = ui.NewButton(currentX, centerY-20, openButtonWidth, 40, "Open", c.showOpenDialog)
+	currentX += openButtonWidth + 20
+
+	testButtonWidth := 100.0
+	c.testButton = ui.NewButton(currentX, centerY-20, testButtonWidth, 40, "Test", c.playTest)
+	currentX += testButtonWidth + 20
+
+	genButtonWidth := 100.0
+	c.genButton = ui.NewButton(currentX, centerY-20, genButtonWidth, 40, "Gen", func() {
+		c.dialog = generateDialog
+	})
+	currentX += genButtonWidth + 40
 
 	currentX += 80
 	nameFieldWidth := 200.0
@@ -94,14 +153,61 @@ func (c *CreateLevelScene) layoutUI() {
 	currentX += 35
 	heightFieldWidth := 60.0
 	c.heightFieldRect = image.Rect(int(currentX), 0, int(currentX+heightFieldWidth), int(fieldHeight))
+	currentX += heightFieldWidth + 40
+
+	toolButtonWidth := 80.0
+	c.toolButtons = make([]*ui.Button, 0, len(editor.Tools()))
+	for _, tool := range editor.Tools() {
+		c.toolButtons = append(c.toolButtons, ui.NewButton(currentX, centerY-20, toolButtonWidth, 40, tool.String(), func() {
+			c.selectTool(tool)
+		}))
+		currentX += toolButtonWidth + 10
+	}
+	currentX += 30
+
+	mirrorButtonWidth := 160.0
+	c.mirrorButton = ui.NewButton(currentX, centerY-20, mirrorButtonWidth, 40, "", func() {
+		c.canvas.Symmetry = c.canvas.Symmetry.Next()
+	})
+	currentX += mirrorButtonWidth + 30
 
+	c.undoButton = ui.NewButton(currentX, centerY-20, toolButtonWidth, 40, "Undo", c.undo)
+	currentX += toolButtonWidth + 10
+	c.redoButton = ui.NewButton(currentX, centerY-20, toolButtonWidth, 40, "Redo", c.redo)
+}
+
+func (c *CreateLevelScene) selectTool(tool editor.Tool) {
+	c.tool = tool
+	c.dragging = false
+	for i, button := range c.toolButtons {
+		if editor.Tools()[i] == tool {
+			button.Color = color.RGBA{R: 0xcc, G: 0x55, B: 0x00, A: 0xff}
+		} else {
+			button.Color = color.RGBA{R: 0x8a, G: 0x2b, B: 0xe2, A: 0xff}
+		}
+	}
+}
+
+func (c *CreateLevelScene) undo() {
+	c.dragging = false
+	if !c.canvas.Undo() {
+		c.accessor.Logger().Debug("nothing to undo")
+	}
+}
+
+func (c *CreateLevelScene) redo() {
+	c.dragging = false
+	if !c.canvas.Redo() {
+		c.accessor.Logger().Debug("nothing to redo")
+	}
 }
 
 func (c *CreateLevelScene) reset() {
 	c.width = MinimalWidth
 	c.height = MinimalHeight
-	c.maximalWidth = c.accessor.Config().ScreenWidth / c.accessor.Config().TileSize
-	c.maximalHeight = c.accessor.Config().ScreenHeight / c.accessor.Config().TileSize
+	// поле любого размера вписывается в окно, поэтому предел — только общий предел уровней
+	c.maximalWidth = levels.MaxGridSize
+	c.maximalHeight = levels.MaxGridSize
 
 	c.LevelName = []rune("new_level")
 	c.widthStr = []rune(strconv.Itoa(c.width))
@@ -111,54 +217,96 @@ func (c *CreateLevelScene) reset() {
 	c.isHeightValid = true
 	c.activeField = ""
 
-	c.walls = make(map[core.Position]bool)
+	c.canvas = editor.NewCanvas(c.width, c.height)
+	c.source = nil
+	c.path = ""
+	c.dialog = noDialog
+	c.dragging = false
+	c.selectTool(editor.Pen)
+	c.status = ""
 
 	c.nextState = core.LevelCreateState
 }
 
+// save сохраняет новый уровень в свободный файл, а для открытого уровня
+// спрашивает, перезаписать ли его файл или сохранить копию.
 func (c *CreateLevelScene) save() {
 	if !c.isNameValid || !c.isWidthValid || !c.isHeightValid {
 		c.accessor.Logger().Warn("Save aborted: invalid data in fields")
 		return
 	}
-
-	walls := c.wallsInSlice()
-	level := core.NewLevel(string(c.LevelName), c.width, c.height, walls)
-	levelsDir := "levels"
-
-	levelJson, err := json.Marshal(level)
-	if err != nil {
-		c.accessor.Logger().Error("failed to marshal level to json", "error", err)
-		c.nextState = core.MainMenuState
-		return
-	}
-
-	if err := os.MkdirAll(levelsDir, 0755); err != nil {
-		c.accessor.Logger().Error("Failed to create levels directory", "path", levelsDir, "error", err)
+	if c.path != "" {
+		c.dialog = overwriteDialog
 		return
 	}
+	c.saveCopy()
+}
 
-	finalPath, err := c.findAvailableFilename(levelsDir, level.Name)
+func (c *CreateLevelScene) saveCopy() {
+	fileName := strings.ReplaceAll(string(c.LevelName), " ", "_")
+	finalPath, err := c.findAvailableFilename(levels.Dir, fileName)
 	if err != nil {
 		c.accessor.Logger().Error("Failed to find available filename", "error", err)
 		return
 	}
+	c.saveTo(finalPath)
+}
 
-	file, err := os.Create(finalPath)
-	if err != nil {
-		c.accessor.Logger().Error("Failed to create file for writing", "path", finalPath, "error", err)
+func (c *CreateLevelScene) saveTo(path string) {
+	c.dialog = noDialog
+	level := c.level()
+
+	// уровень, в который нельзя играть, не сохраняем: показываем причину и остаёмся в редакторе
+	if err := levels.Save(path, level); err != nil {
+		c.accessor.Logger().Error("Failed to save level", "path", path, "error", err)
+		c.status = "CAN'T SAVE: " + levels.Reason(err)
 		return
 	}
-	defer file.Close()
 
-	err = os.WriteFile(finalPath, levelJson, 0644)
-	if err != nil {
-		c.accessor.Logger().Error("Failed to write level file", "path", finalPath, "error", err)
+	c.accessor.Logger().Info("Level saved", "path", path, "name", string(c.LevelName), "w", c.width, "h", c.height)
+	c.status = ""
+	c.nextState = core.MainMenuState
+}
+
+// playTest запускает несохранённый уровень; после смерти змеи или Esc игра возвращается в редактор.
+func (c *CreateLevelScene) playTest() {
+	if !c.isWidthValid || !c.isHeightValid {
+		c.accessor.Logger().Warn("Play test aborted: invalid size")
+		return
+	}
+	level := c.level()
+	if err := levels.Validate(level); err != nil {
+		c.accessor.Logger().Warn("Play test aborted: invalid level", "error", err)
+		c.status = "CAN'T TEST: " + levels.Reason(err)
 		return
 	}
 
-	c.accessor.Logger().Info("Level saved", "name", string(c.LevelName), "w", c.width, "h", c.height)
-	c.nextState = core.MainMenuState
+	c.accessor.Logger().Info("starting play test", "name", level.Name, "w", level.GridWidth, "h", level.GridHeight)
+	c.canvas.EndStroke()
+	c.dragging = false
+	c.status = ""
+	c.testing = true
+	c.nextState = core.GamePlayingState
+	c.accessor.StartGame(GameSetup{
+		Level:       level,
+		Players:     1,
+		ReturnState: core.LevelCreateState,
+		PlayTest:    true,
+	})
+}
+
+// level собирает уровень из редактора; у открытого уровня остальные поля берутся из файла.
+func (c *CreateLevelScene) level() *core.Level {
+	walls := c.canvas.Walls()
+	if c.source == nil {
+		return core.NewLevel(string(c.LevelName), c.width, c.height, walls)
+	}
+	level := *c.source
+	level.Name = string(c.LevelName)
+	level.GridWidth = c.width
+	level.GridHeight = c.height
+	level.Walls = walls
+	return &level
 }
 
 func (c *CreateLevelScene) findAvailableFilename(dir, levelName string) (string, error) {
@@ -178,30 +326,30 @@ func (c *CreateLevelScene) findAvailableFilename(dir, levelName string) (string,
 	}
 }
 
-func (c *CreateLevelScene) wallsInSlice() []core.Wall {
-	walls := make([]core.Wall, 0)
-	for pos, val := range c.walls {
-		if val == true {
-			walls = append(walls, *core.NewWall(pos.X, pos.Y))
-		}
-	}
-	return walls
-}
-
 func (c *CreateLevelScene) Draw(screen *ebiten.Image) {
-	cfg := c.accessor.Config()
+	layout := c.accessor.Screen()
 	assets := c.accessor.Assets()
 
 	screen.Fill(color.NRGBA{R: 0x10, G: 0x10, B: 0x10, A: 0xff})
 
 	topBarImg := assets.WhitePixel
 	opBar := &ebiten.DrawImageOptions{}
-	opBar.GeoM.Scale(float64(cfg.ScreenWidth), float64(cfg.TopBarHeight))
+	opBar.GeoM.Scale(float64(layout.Width), float64(layout.TopBarHeight))
 	opBar.ColorScale.ScaleWithColor(color.Gray{Y: 100})
 	screen.DrawImage(topBarImg, opBar)
 
 	c.resetButton.Draw(screen, assets)
 	c.saveButton.Draw(screen, assets)
+	c.openButton.Draw(screen, assets)
+	c.testButton.Draw(screen, assets)
+	c.genButton.Draw(screen, assets)
+	for _, button := range c.toolButtons {
+		button.Draw(screen, assets)
+	}
+	c.mirrorButton.Text = "Mirror: " + c.canvas.Symmetry.String()
+	c.mirrorButton.Draw(screen, assets)
+	c.undoButton.Draw(screen, assets)
+	c.redoButton.Draw(screen, assets)
 
 	uiFont := assets.UIFont
 	textColor := color.White
@@ -216,29 +364,42 @@ func (c *CreateLevelScene) Draw(screen *ebiten.Image) {
 	text.Draw(screen, "H:", uiFont, c.heightFieldRect.Min.X-35, textYOffset, textColor)
 	c.drawInputField(screen, string(c.heightStr), c.heightFieldRect, "height", c.isHeightValid)
 
-	gridOriginX := 0.0
-	gridOriginY := float64(cfg.TopBarHeight)
-	gridWidthPixels := float64(c.width * cfg.TileSize)
-	gridHeightPixels := float64(c.height * cfg.TileSize)
+	field := c.field()
 
 	// Фон для активной области сетки
-	ui.DrawRectangle(screen, assets, gridOriginX, gridOriginY, gridWidthPixels, gridHeightPixels, color.NRGBA{38, 38, 48, 255})
+	ui.DrawRectangle(screen, assets, field.X, field.Y, field.Width(), field.Height(), color.NRGBA{38, 38, 48, 255})
 
 	// Отрисовка стен
-	for pos, isSet := range c.walls {
-		if !isSet {
-			continue
+	for _, wall := range c.canvas.Walls() {
+		x, y := field.Cell(wall.Position.X, wall.Position.Y)
+		ui.DrawRectangle(screen, assets, x, y, field.TileSize, field.TileSize, color.Gray{Y: 120})
+	}
+
+	// Фигура, которая будет нарисована при отпускании кнопки мыши
+	if c.dragging && c.tool.IsShape() {
+		previewColor := color.NRGBA{R: 120, G: 200, B: 120, A: 140}
+		if !c.dragWall {
+			previewColor = color.NRGBA{R: 200, G: 80, B: 80, A: 140}
 		}
-		if pos.X < c.width && pos.Y < c.height {
-			ui.DrawRectangle(screen, assets,
-				gridOriginX+float64(pos.X*cfg.TileSize),
-				gridOriginY+float64(pos.Y*cfg.TileSize),
-				float64(cfg.TileSize), float64(cfg.TileSize),
-				color.Gray{Y: 120},
-			)
+		for _, pos := range c.tool.Shape(c.dragStart, c.dragEnd) {
+			for _, cell := range c.canvas.Mirrored(pos) {
+				if !c.canvas.IsInside(cell) {
+					continue
+				}
+				x, y := field.Cell(cell.X, cell.Y)
+				ui.DrawRectangle(screen, assets, x, y, field.TileSize, field.TileSize, previewColor)
+			}
 		}
 	}
 
+	if c.status != "" {
+		statusBounds := text.BoundString(uiFont, c.status)
+		text.Draw(screen, c.status, uiFont, (layout.Width-statusBounds.Dx())/2, layout.Height-20, color.RGBA{R: 255, G: 100, B: 100, A: 255})
+	}
+
+	if c.dialog != noDialog {
+		c.drawDialog(screen)
+	}
 }
 
 func (c *CreateLevelScene) drawInputField(screen *ebiten.Image, content string, rect image.Rectangle, fieldName string, isValid bool) {
@@ -283,10 +444,23 @@ func (c *CreateLevelScene) drawInputField(screen *ebiten.Image, content string,
 
 func (c *CreateLevelScene) Update() (core.GameState, error) {
 	c.accessor.Logger().Info("updating create scene", "current_active_field", c.activeField, "name_valid", c.isNameValid, "w_valid", c.isWidthValid, "h_valid", c.isHeightValid)
+	if c.dialog != noDialog {
+		c.updateDialog()
+		return c.nextState, nil
+	}
 	c.handleInput()
 
 	c.saveButton.Update()
 	c.resetButton.Update()
+	c.openButton.Update()
+	c.testButton.Update()
+	c.genButton.Update()
+	for _, button := range c.toolButtons {
+		button.Update()
+	}
+	c.mirrorButton.Update()
+	c.undoButton.Update()
+	c.redoButton.Update()
 
 	if time.Since(c.cursorBlink) > time.Millisecond*500 {
 		c.cursorVisible = !c.cursorVisible
@@ -298,16 +472,14 @@ func (c *CreateLevelScene) Update() (core.GameState, error) {
 
 func (c *CreateLevelScene) handleInput() {
 	c.setActiveField()
+	c.handleShortcuts()
+	if c.dragging && c.activeField != "field" {
+		c.dragging = false
+		c.canvas.EndStroke()
+	}
 	switch c.activeField {
 	case "field":
-		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-			cursorX, cursorY := ebiten.CursorPosition()
-			tileX := cursorX / c.accessor.Config().TileSize
-			tileY := cursorY / c.accessor.Config().TileSize
-			if tileX < c.width && tileY < c.height {
-				c.walls[core.Position{X: tileX, Y: tileY}] = !c.walls[core.Position{X: tileX, Y: tileY}]
-			}
-		}
+		c.handleDrawing()
 	case "name":
 		inputChars := ebiten.AppendInputChars(c.LevelName)
 		if len(inputChars) > MaxLevelName {
@@ -338,6 +510,94 @@ func (c *CreateLevelScene) handleInput() {
 	c.validateInputs()
 }
 
+// handleShortcuts обрабатывает Ctrl+Z / Ctrl+Y (или Ctrl+Shift+Z), а вне текстовых полей —
+// выбор инструмента клавишами 1-4 и переключение симметрии клавишей M.
+func (c *CreateLevelScene) handleShortcuts() {
+	if ebiten.IsKeyPressed(ebiten.KeyControl) || ebiten.IsKeyPressed(ebiten.KeyMeta) {
+		if inpututil.IsKeyJustPressed(ebiten.KeyZ) && ebiten.IsKeyPressed(ebiten.KeyShift) || inpututil.IsKeyJustPressed(ebiten.KeyY) {
+			c.redo()
+		} else if inpututil.IsKeyJustPressed(ebiten.KeyZ) {
+			c.undo()
+		}
+		return
+	}
+
+	if c.activeField != "" && c.activeField != "field" {
+		return
+	}
+	for i, key := range []ebiten.Key{ebiten.Key1, ebiten.Key2, ebiten.Key3, ebiten.Key4} {
+		if inpututil.IsKeyJustPressed(key) {
+			c.selectTool(editor.Tools()[i])
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyM) {
+		c.canvas.Symmetry = c.canvas.Symmetry.Next()
+	}
+}
+
+// field — место редактируемого поля на экране: оно вписывается в экран под верхней панелью.
+func (c *CreateLevelScene) field() ui.Field {
+	return c.accessor.Screen().Field(c.width, c.height)
+}
+
+// cursorCell возвращает клетку поля под курсором; false — курсор вне поля.
+func (c *CreateLevelScene) cursorCell() (core.Position, bool) {
+	x, y, ok := c.field().CellAt(ebiten.CursorPosition())
+	pos := core.Position{X: x, Y: y}
+	return pos, ok && c.canvas.IsInside(pos)
+}
+
+// handleDrawing рисует стены левой кнопкой мыши и стирает правой выбранным инструментом.
+func (c *CreateLevelScene) handleDrawing() {
+	pos, inside := c.cursorCell()
+
+	if !c.dragging {
+		leftPressed := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+		rightPressed := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight)
+		if !inside || !leftPressed && !rightPressed {
+			return
+		}
+		c.dragWall = leftPressed
+		c.dragStart, c.dragEnd = pos, pos
+
+		switch {
+		case c.tool == editor.Fill:
+			c.canvas.SetAll(c.canvas.FillCells(pos), c.dragWall)
+			return
+		case c.tool == editor.Pen:
+			c.canvas.BeginStroke()
+			c.canvas.Set(pos, c.dragWall)
+		}
+		c.dragging = true
+		return
+	}
+
+	button := ebiten.MouseButtonRight
+	if c.dragWall {
+		button = ebiten.MouseButtonLeft
+	}
+
+	if inside && pos != c.dragEnd {
+		if c.tool == editor.Pen {
+			// при быстром движении мыши курсор перескакивает клетки: дорисовываем отрезок
+			for _, cell := range editor.LineCells(c.dragEnd, pos) {
+				c.canvas.Set(cell, c.dragWall)
+			}
+		}
+		c.dragEnd = pos
+	}
+
+	if ebiten.IsMouseButtonPressed(button) {
+		return
+	}
+	c.dragging = false
+	if c.tool.IsShape() {
+		c.canvas.SetAll(c.tool.Shape(c.dragStart, c.dragEnd), c.dragWall)
+	} else {
+		c.canvas.EndStroke()
+	}
+}
+
 func (c *CreateLevelScene) validateInputs() {
 	w, err := strconv.Atoi(string(c.widthStr))
 	if err == nil {
@@ -368,9 +628,10 @@ func (c *CreateLevelScene) validateInputs() {
 	} else {
 		c.isHeightValid = false
 	}
+	c.canvas.Resize(c.width, c.height)
 
 	name := string(c.LevelName)
-	invalidChars := "/\\:*?\"<>| "
+	invalidChars := "/\\:*?\"<>|"
 	if len(name) == 0 || strings.ContainsAny(name, invalidChars) {
 		c.isNameValid = false
 	} else {
@@ -380,11 +641,10 @@ func (c *CreateLevelScene) validateInputs() {
 }
 
 func (c *CreateLevelScene) setActiveField() {
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) || inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
 		cursorX, cursorY := ebiten.CursorPosition()
 		mousePoint := image.Pt(cursorX, cursorY)
-		if cursorX >= 0 && cursorX < c.accessor.Config().TileSize*c.width &&
-			cursorY >= c.accessor.Config().TopBarHeight && cursorY < c.accessor.Config().TopBarHeight+c.accessor.Config().TileSize*c.height {
+		if _, onField := c.cursorCell(); onField {
 			c.activeField = "field"
 		} else if mousePoint.In(c.nameFieldRect) {
 			c.activeField = "name"
@@ -400,5 +660,12 @@ func (c *CreateLevelScene) setActiveField() {
 }
 
 func (c *CreateLevelScene) OnEnter() {
+	if c.testing {
+		// возвращение из пробной игры: уровень, история правок и выбранный инструмент остаются
+		c.testing = false
+		c.nextState = core.LevelCreateState
+		c.activeField = ""
+		return
+	}
 	c.reset()
 }
@@ -9,8 +9,10 @@ import (
 	"image"
 	"image/color"
 	"snake-game/internal/core"
+	"snake-game/internal/replay"
 	"snake-game/internal/storage"
 	"snake-game/internal/ui"
+	"strings"
 	"time"
 )
 
@@ -21,105 +23,211 @@ const (
 type GameOverScene struct {
 	accessor GameAccessor
 
+	setup GameSetup
 	level *core.Level
 
 	nextState core.GameState
 
-	newGameButton   *ui.Button
-	mainMenuButton  *ui.Button
-	saveScoreButton *ui.Button
-	nameFieldRect   image.Rectangle
-
-	isRecordSaved bool
-	playerName    []rune
+	newGameButton     *ui.Button
+	mainMenuButton    *ui.Button
+	watchReplayButton *ui.Button
+	saveScoreButton   *ui.Button
+	// по одному полю имени на каждого игрока-человека, в порядке humans
+	nameFieldRects []image.Rectangle
+
+	// replayFile — имя уже сохранённого повтора, чтобы повторное нажатие не писало его снова
+	replayFile  string
+	playerNames [][]rune
+	// humans — индексы игроков-людей; за ботов рекорды не сохраняются
+	humans []int
+	// saved[i] — рекорд игрока humans[i] уже в хранилище
+	saved      []bool
+	activeName int
 }
 
-func NewGameOverScene(accessor GameAccessor, level *core.Level) *GameOverScene {
+func NewGameOverScene(accessor GameAccessor, setup GameSetup) *GameOverScene {
 	scene := &GameOverScene{
-		accessor:  accessor,
-		level:     level,
-		nextState: core.GameOverState,
+		accessor:    accessor,
+		setup:       setup,
+		level:       setup.Level,
+		nextState:   core.GameOverState,
+		playerNames: make([][]rune, max(setup.Players, 1)),
+	}
+	for player := range scene.playerNames {
+		if bot := setup.bot(player); bot != nil {
+			scene.playerNames[player] = []rune(strings.ToUpper(bot.Name()) + " BOT")
+		} else {
+			scene.humans = append(scene.humans, player)
+		}
+	}
+	if len(scene.humans) > 0 {
+		scene.playerNames[scene.humans[0]] = []rune(setup.PlayerName)
 	}
+	scene.saved = make([]bool, len(scene.humans))
+
+	scene.Relayout()
+	return scene
+}
 
-	cfg := scene.accessor.Config()
-	centerX := float64(cfg.ScreenWidth) / 2
+// Relayout раскладывает кнопки и поля имён по центру экрана.
+func (s *GameOverScene) Relayout() {
+	layout := s.accessor.Screen()
+	centerX := float64(layout.Width) / 2
+
+	s.nameFieldRects = nil
 
 	newGameButton := ui.NewButton(
 		centerX-120,
-		float64(cfg.ScreenHeight/2)+90,
+		float64(layout.Height/2)+90,
 		240,
 		50,
 		"NEW GAME",
 		func() {
-			accessor.StartGame(level)
-			scene.nextState = core.GamePlayingState
+			s.accessor.StartGame(s.setup)
+			s.nextState = core.GamePlayingState
 		},
 	)
 
-	scene.newGameButton = newGameButton
+	s.newGameButton = newGameButton
 
+	exitText := "MAIN MENU"
+	if s.setup.ReturnState != core.MainMenuState {
+		exitText = "BACK"
+	}
 	mainMenuButton := ui.NewButton(
 		centerX-120,
-		float64(cfg.ScreenHeight/2)+145,
+		float64(layout.Height/2)+145,
 		240,
 		50,
-		"MAIN MENU",
+		exitText,
 		func() {
-			scene.nextState = core.MainMenuState
+			s.nextState = s.setup.ReturnState
 		})
 
-	scene.mainMenuButton = mainMenuButton
+	s.mainMenuButton = mainMenuButton
 
-	saveButton := ui.NewButton(
-		centerX+130,
-		float64(cfg.ScreenHeight/2)+38,
-		40,
-		40,
-		"S",
+	watchReplayButton := ui.NewButton(
+		centerX-120,
+		float64(layout.Height/2)+200,
+		240,
+		50,
+		"WATCH REPLAY",
 		func() {
-			if scene.isRecordSaved == true {
+			rep := s.accessor.LastReplay()
+			if rep == nil {
+				s.accessor.Logger().Warn("no replay recorded for this game")
 				return
 			}
-			record := storage.NewRecord(string(scene.playerName), scene.accessor.Score(), scene.accessor.GameTime(), scene.level.Name, time.Now())
-			err := scene.accessor.Repository().SaveRecord(context.Background(), record)
-			if err != nil {
-				scene.accessor.Logger().Error("failed to save record", "error", err)
-			} else {
-				scene.isRecordSaved = true
-			}
+			s.accessor.WatchReplay(rep, core.GameOverState)
+			s.nextState = core.ReplayState
 		})
 
-	scene.saveScoreButton = saveButton
+	s.watchReplayButton = watchReplayButton
 
 	inputFieldWidth := 240.0
 	inputFieldHeight := 40.0
-	inputX := centerX - 120
-	inputY := float64(cfg.ScreenHeight/2) + 38
-	scene.nameFieldRect = image.Rect(int(inputX), int(inputY), int(inputX+inputFieldWidth), int(inputY+inputFieldHeight))
+	inputY := float64(layout.Height/2) + 38
+	fieldsWidth := float64(len(s.humans))*(inputFieldWidth+20) - 20
+	inputX := centerX - fieldsWidth/2
+	for range s.humans {
+		rect := image.Rect(int(inputX), int(inputY), int(inputX+inputFieldWidth), int(inputY+inputFieldHeight))
+		s.nameFieldRects = append(s.nameFieldRects, rect)
+		inputX += inputFieldWidth + 20
+	}
 
-	return scene
+	saveButton := ui.NewButton(
+		inputX-10,
+		inputY,
+		40,
+		40,
+		"S",
+		s.saveRecords)
+
+	s.saveScoreButton = saveButton
+}
+
+// saveRecords сохраняет повтор и по записи на каждого игрока со ссылкой на этот повтор.
+// Если запись одного из игроков не сохранилась, повторное нажатие досохраняет только
+// недостающие записи, а повтор уже не пишется заново.
+func (s *GameOverScene) saveRecords() {
+	if s.replayFile == "" {
+		if rep := s.accessor.LastReplay(); rep != nil {
+			replayFile, err := rep.Save(replay.Dir)
+			if err != nil {
+				s.accessor.Logger().Error("failed to save replay", "error", err)
+			}
+			s.replayFile = replayFile
+		}
+	}
+
+	for i, player := range s.humans {
+		if s.saved[i] {
+			continue
+		}
+		record := storage.NewRecord(string(s.playerNames[player]), s.accessor.Score(player), s.accessor.GameTime(), s.level.Name, time.Now())
+		record.Mode = s.setup.Mode.Name()
+		record.ReplayFile = s.replayFile
+		err := s.accessor.Repository().SaveRecord(context.Background(), record)
+		if err != nil {
+			s.accessor.Logger().Error("failed to save record", "player", player, "error", err)
+			return
+		}
+		s.saved[i] = true
+	}
+}
+
+// resultText объявляет победителя, если игроков несколько, или итог уровня кампании.
+func (s *GameOverScene) resultText() string {
+	rep := s.accessor.LastReplay()
+	if s.setup.TargetScore > 0 {
+		if s.accessor.Score(0) >= s.setup.TargetScore {
+			return "LEVEL COMPLETE!"
+		}
+		return fmt.Sprintf("TARGET: %d", s.setup.TargetScore)
+	}
+	if len(s.playerNames) < 2 || rep == nil {
+		return ""
+	}
+	if rep.Winner < 0 {
+		return "DRAW!"
+	}
+	name := strings.TrimSpace(string(s.playerNames[rep.Winner]))
+	if name == "" {
+		name = fmt.Sprintf("PLAYER %d", rep.Winner+1)
+	}
+	return strings.ToUpper(name) + " WINS!"
 }
 
 func (s *GameOverScene) Draw(screen *ebiten.Image) {
-	cfg := s.accessor.Config()
+	layout := s.accessor.Screen()
 	assets := s.accessor.Assets()
 
 	opOverlay := &ebiten.DrawImageOptions{}
-	opOverlay.GeoM.Scale(float64(cfg.ScreenWidth), float64(cfg.ScreenHeight))
+	opOverlay.GeoM.Scale(float64(layout.Width), float64(layout.Height))
 
 	opOverlay.ColorScale.Scale(0, 0, 0, 0.66)
 	screen.DrawImage(assets.WhitePixel, opOverlay)
 
 	uiFont := assets.UIFont
-	centerX := cfg.ScreenWidth / 2
+	centerX := layout.Width / 2
 
 	gameOverText := "GAME OVER"
 	gameOverBounds := text.BoundString(uiFont, gameOverText)
 	gameOverX := centerX - gameOverBounds.Dx()/2
-	gameOverY := cfg.WindowHeight()/2 - 80
+	gameOverY := layout.Height/2 - 80
 	text.Draw(screen, gameOverText, uiFont, gameOverX, gameOverY, color.White)
 
-	scoreStr := fmt.Sprintf("FINAL SCORE: %d", s.accessor.Score())
+	if result := s.resultText(); result != "" {
+		resultBounds := text.BoundString(uiFont, result)
+		text.Draw(screen, result, uiFont, centerX-resultBounds.Dx()/2, gameOverY+20, color.RGBA{R: 255, G: 215, B: 0, A: 255})
+	}
+
+	var scoreStr string
+	if len(s.playerNames) > 1 {
+		scoreStr = strings.ToUpper(s.setup.Mode.Name()) + "  " + scoresText(len(s.playerNames), s.accessor.Score)
+	} else {
+		scoreStr = fmt.Sprintf("%s  FINAL SCORE: %d", strings.ToUpper(s.setup.Mode.Name()), s.accessor.Score(0))
+	}
 	seconds := int(s.accessor.GameTime().Seconds())
 	timeStr := fmt.Sprintf("TIME: %02d:%02d", seconds/60, seconds%60)
 
@@ -133,43 +241,74 @@ func (s *GameOverScene) Draw(screen *ebiten.Image) {
 	timeY := scoreY + 25
 	text.Draw(screen, timeStr, uiFont, timeX, timeY, color.White)
 
-	s.drawInputField(screen)
+	for field := range s.humans {
+		s.drawInputField(screen, field)
+	}
 
 	s.newGameButton.Draw(screen, assets)
 	s.mainMenuButton.Draw(screen, assets)
+	s.watchReplayButton.Draw(screen, assets)
 	s.saveScoreButton.Draw(screen, assets)
 }
 
-func (s *GameOverScene) drawInputField(screen *ebiten.Image) {
+func (s *GameOverScene) drawInputField(screen *ebiten.Image, field int) {
 	assets := s.accessor.Assets()
-	rect := s.nameFieldRect
+	rect := s.nameFieldRects[field]
+	player := s.humans[field]
+
+	var borderColor color.Color = color.Gray{Y: 100}
+	if len(s.playerNames) > 1 {
+		if field == s.activeName {
+			borderColor = color.White
+		}
+		text.Draw(screen, fmt.Sprintf("P%d", player+1), assets.UIFont, rect.Min.X, rect.Min.Y-8, playerTint(player))
+	}
 
-	ui.DrawRectangle(screen, assets, float64(rect.Min.X-2), float64(rect.Min.Y-2), float64(rect.Dx()+4), float64(rect.Dy()+4), color.Gray{Y: 100})
+	ui.DrawRectangle(screen, assets, float64(rect.Min.X-2), float64(rect.Min.Y-2), float64(rect.Dx()+4), float64(rect.Dy()+4), borderColor)
 	ui.DrawRectangle(screen, assets, float64(rect.Min.X), float64(rect.Min.Y), float64(rect.Dx()), float64(rect.Dy()), color.Black)
-	text.Draw(screen, string(s.playerName), assets.UIFont, rect.Min.X+15, rect.Min.Y+28, color.White)
+	text.Draw(screen, string(s.playerNames[player]), assets.UIFont, rect.Min.X+15, rect.Min.Y+28, color.White)
 }
 
 func (s *GameOverScene) Update() (core.GameState, error) {
 	s.handleInput()
 	s.newGameButton.Update()
 	s.mainMenuButton.Update()
+	s.watchReplayButton.Update()
 	s.saveScoreButton.Update()
 	return s.nextState, nil
 }
 
 func (s *GameOverScene) handleInput() {
-	s.playerName = ebiten.AppendInputChars(s.playerName)
-	if len(s.playerName) > MaxPlayerName {
-		s.playerName = s.playerName[0:MaxPlayerName]
+	if len(s.humans) == 0 {
+		return
+	}
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		cursorX, cursorY := ebiten.CursorPosition()
+		for field, rect := range s.nameFieldRects {
+			if image.Pt(cursorX, cursorY).In(rect) {
+				s.activeName = field
+			}
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+		s.activeName = (s.activeName + 1) % len(s.humans)
+	}
+
+	player := s.humans[s.activeName]
+	name := ebiten.AppendInputChars(s.playerNames[player])
+	if len(name) > MaxPlayerName {
+		name = name[0:MaxPlayerName]
 	}
 	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) {
-		if len(s.playerName) > 0 {
-			s.playerName = s.playerName[0 : len(s.playerName)-1]
+		if len(name) > 0 {
+			name = name[0 : len(name)-1]
 		}
 	}
+	s.playerNames[player] = name
 }
 
 func (s *GameOverScene) OnEnter() {
+	// сцена создаётся заново для каждой игры, поэтому после просмотра
+	// повтора флаг сохранения рекорда не сбрасываем
 	s.nextState = core.GameOverState
-	s.isRecordSaved = false
 }
@@ -0,0 +1,288 @@
+package scenes
+
+import (
+	"context"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"image/color"
+	"snake-game/internal/config"
+	"snake-game/internal/core"
+	"snake-game/internal/netplay"
+	"snake-game/internal/sim"
+	"snake-game/internal/ui"
+	"strings"
+)
+
+const (
+	MaxServerAddress = 40
+)
+
+type dialResult struct {
+	client *netplay.Client
+	err    error
+}
+
+// JoinScene подключается к snake-server и показывает партию, которую ведёт сервер.
+// Локальный мир не симулируется, а только обновляется снимками с сервера.
+type JoinScene struct {
+	accessor GameAccessor
+
+	address    []rune
+	status     string
+	dialed     chan dialResult
+	cancelDial context.CancelFunc
+
+	client *netplay.Client
+	world  *sim.World
+	state  netplay.State
+
+	connectButton *ui.Button
+	backButton    *ui.Button
+
+	nextState core.GameState
+}
+
+func NewJoinScene(accessor GameAccessor) *JoinScene {
+	scene := &JoinScene{
+		accessor:  accessor,
+		address:   []rune(netplay.DefaultAddr),
+		nextState: core.JoinGameState,
+	}
+
+	scene.Relayout()
+	return scene
+}
+
+// Relayout ставит кнопки под полем адреса по центру экрана.
+func (s *JoinScene) Relayout() {
+	layout := s.accessor.Screen()
+	centerX := float64(layout.Width) / 2
+	startY := float64(layout.Height/2) + 40
+
+	s.connectButton = ui.NewButton(centerX-120, startY, 240, 50, "CONNECT", s.connect)
+	s.backButton = ui.NewButton(centerX-120, startY+60, 240, 50, "MAIN MENU", s.leave)
+}
+
+func (s *JoinScene) connect() {
+	if s.dialed != nil || s.client != nil {
+		return
+	}
+
+	address := strings.TrimSpace(string(s.address))
+	s.accessor.Logger().Info("connecting to server", "addr", address)
+	s.status = "waiting for other players..."
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dialed := make(chan dialResult, 1)
+	s.cancelDial = cancel
+	s.dialed = dialed
+	go func() {
+		client, err := netplay.Dial(ctx, address, "")
+		dialed <- dialResult{client: client, err: err}
+	}()
+}
+
+func (s *JoinScene) leave() {
+	s.disconnect()
+	s.nextState = core.MainMenuState
+}
+
+func (s *JoinScene) disconnect() {
+	if s.cancelDial != nil {
+		s.cancelDial()
+		s.cancelDial = nil
+	}
+	if s.dialed != nil {
+		// подключение могло успеть завершиться до отмены — закрываем его, когда придёт результат
+		go func(dialed <-chan dialResult) {
+			if result := <-dialed; result.client != nil {
+				_ = result.client.Close()
+			}
+		}(s.dialed)
+		s.dialed = nil
+	}
+	if s.client != nil {
+		if err := s.client.Close(); err != nil {
+			s.accessor.Logger().Warn("failed to close connection", "error", err)
+		}
+		s.client = nil
+	}
+	s.world = nil
+}
+
+func (s *JoinScene) Update() (core.GameState, error) {
+	if s.accessor.Input().JustPressed(config.ActionBack) {
+		s.leave()
+		return s.nextState, nil
+	}
+
+	if s.dialed != nil {
+		select {
+		case result := <-s.dialed:
+			s.dialed = nil
+			s.cancelDial = nil
+			s.handleDial(result)
+		default:
+		}
+	}
+
+	if s.client == nil {
+		s.handleAddressInput()
+		s.connectButton.Update()
+		s.backButton.Update()
+		return s.nextState, nil
+	}
+
+	for _, direction := range s.accessor.Input().Turns(0) {
+		if err := s.client.SendTurn(direction); err != nil {
+			s.accessor.Logger().Warn("failed to send turn", "error", err)
+		}
+	}
+
+	if state, ok := s.client.State(); ok && state.Tick != s.state.Tick {
+		s.state = state
+		state.Apply(s.world)
+		if state.Over {
+			s.accessor.Logger().Info("online game finished", "scores", state.Scores, "reason", state.Reason.String())
+		}
+	}
+	if err := s.client.Err(); err != nil && !s.world.IsOver() && s.status == "" {
+		s.accessor.Logger().Warn("connection lost", "error", err)
+		s.status = "connection lost"
+	}
+	return s.nextState, nil
+}
+
+func (s *JoinScene) handleDial(result dialResult) {
+	if result.err != nil {
+		s.accessor.Logger().Error("failed to join game", "error", result.err)
+		s.status = "failed to connect"
+		return
+	}
+
+	world, err := result.client.NewWorld()
+	if err != nil {
+		s.accessor.Logger().Error("failed to create world for online game", "error", err)
+		s.status = "unsupported game settings"
+		_ = result.client.Close()
+		return
+	}
+
+	welcome := result.client.Welcome()
+	s.accessor.Logger().Info("joined online game", "player", welcome.Player, "players", welcome.Names, "mode", welcome.Mode)
+	s.client = result.client
+	s.world = world
+	s.state = netplay.State{}
+	s.status = ""
+}
+
+func (s *JoinScene) handleAddressInput() {
+	if s.dialed != nil {
+		return
+	}
+	s.address = ebiten.AppendInputChars(s.address)
+	if len(s.address) > MaxServerAddress {
+		s.address = s.address[0:MaxServerAddress]
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) {
+		if len(s.address) > 0 {
+			s.address = s.address[0 : len(s.address)-1]
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		s.connect()
+	}
+}
+
+func (s *JoinScene) Draw(screen *ebiten.Image) {
+	if s.world != nil {
+		s.drawGame(screen)
+		return
+	}
+
+	layout := s.accessor.Screen()
+	assets := s.accessor.Assets()
+	centerX := layout.Width / 2
+
+	screen.Fill(color.RGBA{R: 20, G: 20, B: 40, A: 255})
+
+	titleText := "JOIN GAME"
+	titleBounds := text.BoundString(assets.TitleFont, titleText)
+	text.Draw(screen, titleText, assets.TitleFont, centerX-titleBounds.Dx()/2, 50, color.White)
+
+	fieldWidth := 480.0
+	fieldHeight := 40.0
+	fieldX := float64(centerX) - fieldWidth/2
+	fieldY := float64(layout.Height/2) - 40
+
+	labelText := "Server:"
+	labelBounds := text.BoundString(assets.UIFont, labelText)
+	text.Draw(screen, labelText, assets.UIFont, int(fieldX)-10-labelBounds.Dx(), int(fieldY)+28, color.White)
+
+	ui.DrawRectangle(screen, assets, fieldX-2, fieldY-2, fieldWidth+4, fieldHeight+4, color.Gray{Y: 128})
+	ui.DrawRectangle(screen, assets, fieldX, fieldY, fieldWidth, fieldHeight, color.Black)
+	text.Draw(screen, string(s.address), assets.UIFont, int(fieldX)+15, int(fieldY)+28, color.White)
+
+	if s.status != "" {
+		statusBounds := text.BoundString(assets.UIFont, s.status)
+		text.Draw(screen, s.status, assets.UIFont, centerX-statusBounds.Dx()/2, int(fieldY)-20, color.RGBA{R: 255, G: 215, B: 0, A: 255})
+	}
+
+	s.connectButton.Draw(screen, assets)
+	s.backButton.Draw(screen, assets)
+}
+
+func (s *JoinScene) drawGame(screen *ebiten.Image) {
+	layout := s.accessor.Screen()
+	assets := s.accessor.Assets()
+	welcome := s.client.Welcome()
+
+	screen.Fill(color.RGBA{R: 5, G: 5, B: 15, A: 255})
+
+	scoreStr := scoresText(s.world.Players(), func(player int) int {
+		return s.world.Scores[player]
+	})
+	seconds := s.world.Tick / sim.TicksPerSecond
+	timeStr := fmt.Sprintf("TIME: %02d:%02d", seconds/60, seconds%60)
+	centerStr := fmt.Sprintf("ONLINE %s  YOU: P%d", strings.ToUpper(welcome.Mode), welcome.Player+1)
+
+	drawWorld(screen, s.accessor, s.world)
+	drawTopBar(screen, s.accessor, scoreStr, centerStr, timeStr)
+
+	controls := s.accessor.Input()
+	back := keyHint(controls, config.ActionBack)
+	var hint string
+	switch {
+	case s.world.IsOver():
+		hint = s.resultText() + "  " + back + " - main menu"
+	case s.status != "":
+		hint = strings.ToUpper(s.status) + ". " + back + " - main menu"
+	default:
+		hint = keyHint(controls, config.PlayerActions(0)...) + " - turn, " + back + " - leave"
+	}
+	hintBounds := text.BoundString(assets.UIFont, hint)
+	text.Draw(screen, hint, assets.UIFont, (layout.Width-hintBounds.Dx())/2, layout.Height-20, color.White)
+}
+
+func (s *JoinScene) resultText() string {
+	welcome := s.client.Welcome()
+	switch {
+	case welcome.Players < 2:
+		return "GAME OVER."
+	case s.state.Winner < 0:
+		return "DRAW!"
+	case s.state.Winner == welcome.Player:
+		return "YOU WIN!"
+	default:
+		return fmt.Sprintf("%s WINS!", strings.ToUpper(welcome.Names[s.state.Winner]))
+	}
+}
+
+func (s *JoinScene) OnEnter() {
+	s.accessor.Logger().Info("Entering join game scene")
+	s.disconnect()
+	s.status = ""
+	s.nextState = core.JoinGameState
+}
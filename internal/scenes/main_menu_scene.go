@@ -1,30 +1,61 @@
 package scenes
 
 import (
-	"encoding/json"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text"
 	"image/color"
-	"io/fs"
+	"math/rand/v2"
 	"os"
-	"path"
-	"path/filepath"
+	"snake-game/internal/ai"
+	"snake-game/internal/config"
 	"snake-game/internal/core"
+	"snake-game/internal/levels"
+	"snake-game/internal/sim"
 	"snake-game/internal/ui"
 	"strings"
 )
 
+// lineup — состав игроков, выбираемый в меню; bot управляет вторым игроком вместо человека.
+type lineup struct {
+	label   string
+	players int
+	bot     ai.Controller
+}
+
+func lineups() []lineup {
+	result := []lineup{
+		{label: "1 PLAYER", players: 1},
+		{label: "2 PLAYERS", players: 2},
+	}
+	for _, controller := range ai.Controllers() {
+		result = append(result, lineup{label: "VS " + strings.ToUpper(controller.Name()), players: 2, bot: controller})
+	}
+	return result
+}
+
 type MainMenuScene struct {
 	accessor GameAccessor
 
-	levelNames   []string
+	levels       []levels.Entry
 	currentLevel int
 
+	modes       []sim.GameMode
+	currentMode int
+
+	lineups       []lineup
+	currentLineup int
+
+	// demoWorld крутится на фоне меню: змеями управляют боты
+	demoWorld *sim.World
+	demoBots  []ai.Controller
+
 	nextState         core.GameState
 	newGameButton     *ui.Button
+	campaignButton    *ui.Button
+	joinGameButton    *ui.Button
 	createLevelButton *ui.Button
 	rankingButton     *ui.Button
+	settingsButton    *ui.Button
 	quitButton        *ui.Button
 }
 
@@ -32,38 +63,67 @@ func NewMainMenuScene(accessor GameAccessor) *MainMenuScene {
 	scene := &MainMenuScene{
 		accessor:  accessor,
 		nextState: core.MainMenuState,
+		modes:     sim.Modes(),
+		lineups:   lineups(),
+		demoBots:  []ai.Controller{ai.BFS{}, ai.Greedy{}},
 	}
 
-	cfg := scene.accessor.Config()
-	centerX := float64(cfg.ScreenWidth) / 2
-	startY := float64(cfg.ScreenHeight/2) + 80
+	scene.Relayout()
+	return scene
+}
+
+// Relayout раскладывает кнопки меню по центру экрана.
+func (s *MainMenuScene) Relayout() {
+	layout := s.accessor.Screen()
+	centerX := float64(layout.Width) / 2
+	startY := float64(layout.Height/2) + 80
 	buttonWidth := float64(240)
 	buttonHeight := float64(50)
 	buttonSpacing := buttonHeight + 10
 
-	newGameButton := ui.NewButton(centerX-120, startY, buttonWidth, buttonHeight, "NEW GAME", scene.newGame)
-	createLevelButton := ui.NewButton(centerX-120, startY+buttonSpacing, buttonWidth, buttonHeight, "CREATE LEVEL", scene.createLevel)
-	rankingButton := ui.NewButton(centerX-120, startY+2*buttonSpacing, buttonWidth, buttonHeight, "RANKING", scene.ranking)
-	quitButton := ui.NewButton(centerX-120, startY+3*buttonSpacing, buttonWidth, buttonHeight, "QUIT",
+	newGameButton := ui.NewButton(centerX-120, startY, buttonWidth, buttonHeight, "NEW GAME", s.newGame)
+	campaignButton := ui.NewButton(centerX-120, startY+buttonSpacing, buttonWidth, buttonHeight, "CAMPAIGN", s.campaign)
+	joinGameButton := ui.NewButton(centerX-120, startY+2*buttonSpacing, buttonWidth, buttonHeight, "JOIN GAME", s.joinGame)
+	createLevelButton := ui.NewButton(centerX-120, startY+3*buttonSpacing, buttonWidth, buttonHeight, "CREATE LEVEL", s.createLevel)
+	rankingButton := ui.NewButton(centerX-120, startY+4*buttonSpacing, buttonWidth, buttonHeight, "RANKING", s.ranking)
+	settingsButton := ui.NewButton(centerX-120, startY+5*buttonSpacing, buttonWidth, buttonHeight, "SETTINGS", s.settings)
+	quitButton := ui.NewButton(centerX-120, startY+6*buttonSpacing, buttonWidth, buttonHeight, "QUIT",
 		func() {
 			os.Exit(0)
 		},
 	)
 
-	scene.newGameButton = newGameButton
-	scene.createLevelButton = createLevelButton
-	scene.rankingButton = rankingButton
-	scene.quitButton = quitButton
-
-	return scene
+	s.newGameButton = newGameButton
+	s.campaignButton = campaignButton
+	s.joinGameButton = joinGameButton
+	s.createLevelButton = createLevelButton
+	s.rankingButton = rankingButton
+	s.settingsButton = settingsButton
+	s.quitButton = quitButton
+
+	// после изменения окна фоновая партия начинается заново на поле под новый экран
+	if s.demoWorld != nil {
+		level := s.demoLevel()
+		if level.GridWidth != s.demoWorld.Level.GridWidth || level.GridHeight != s.demoWorld.Level.GridHeight {
+			s.startDemo()
+		}
+	}
 }
 
 func (s *MainMenuScene) Draw(screen *ebiten.Image) {
-	cfg := s.accessor.Config()
 	assets := s.accessor.Assets()
-	centerX := cfg.ScreenWidth / 2
+	layout := s.accessor.Screen()
+	centerX := layout.Width / 2
 
 	screen.Fill(color.RGBA{R: 20, G: 20, B: 40, A: 255})
+	if s.demoWorld != nil {
+		drawWorld(screen, s.accessor, s.demoWorld)
+
+		opOverlay := &ebiten.DrawImageOptions{}
+		opOverlay.GeoM.Scale(float64(layout.Width), float64(layout.Height))
+		opOverlay.ColorScale.Scale(0, 0, 0, 0.7)
+		screen.DrawImage(assets.WhitePixel, opOverlay)
+	}
 
 	titleFont := assets.TitleFont
 	titleText := "SNAKE GAME"
@@ -74,48 +134,75 @@ func (s *MainMenuScene) Draw(screen *ebiten.Image) {
 	s.drawLevelSelector(screen)
 
 	s.newGameButton.Draw(screen, assets)
+	s.campaignButton.Draw(screen, assets)
+	s.joinGameButton.Draw(screen, assets)
 	s.createLevelButton.Draw(screen, assets)
 	s.rankingButton.Draw(screen, assets)
+	s.settingsButton.Draw(screen, assets)
 	s.quitButton.Draw(screen, assets)
 }
 
 func (s *MainMenuScene) drawLevelSelector(screen *ebiten.Image) {
+	levelY := float64(s.accessor.Screen().Height/2) - 80
+	if len(s.levels) > 0 {
+		entry := s.levels[s.currentLevel]
+		if err := entry.ErrFor(s.lineups[s.currentLineup].players); err == nil {
+			s.drawSelector(screen, "Select level:", entry.Name(), levelY, color.White)
+		} else {
+			// в неисправный уровень (или негодный для выбранного числа игроков) играть нельзя:
+			// показываем его серым и объясняем почему
+			s.drawSelector(screen, "Select level:", entry.Name(), levelY, color.Gray{Y: 100})
+			s.drawLevelProblem(screen, levels.Reason(err), levelY-50)
+		}
+	} else {
+		s.drawSelector(screen, "Select level:", "Levels not found", levelY, color.White)
+	}
+
+	modeName := "< " + s.modes[s.currentMode].Name() + " >"
+	s.drawSelector(screen, "Select mode:", modeName, levelY+60, color.White)
+
+	s.drawSelector(screen, "Players ("+keyHint(s.accessor.Input(), config.ActionLineup)+"):", s.lineups[s.currentLineup].label, levelY+120, color.White)
+}
+
+func (s *MainMenuScene) drawLevelProblem(screen *ebiten.Image, reason string, y float64) {
 	assets := s.accessor.Assets()
-	cfg := s.accessor.Config()
+	layout := s.accessor.Screen()
 
-	centerX := cfg.ScreenWidth / 2
+	problem := "INVALID LEVEL: " + reason
+	problemBounds := text.BoundString(assets.UIFont, problem)
+	text.Draw(screen, problem, assets.UIFont, (layout.Width-problemBounds.Dx())/2, int(y), color.RGBA{R: 255, G: 100, B: 100, A: 255})
+}
 
-	labelText := "Select level:"
-	labelBounds := text.BoundString(assets.UIFont, labelText)
-	labelX := centerX - labelBounds.Dx()/2 - 60
-	labelY := float64(cfg.ScreenHeight/2) - 80
-	text.Draw(screen, labelText, assets.UIFont, labelX, int(labelY), color.White)
+func (s *MainMenuScene) drawSelector(screen *ebiten.Image, labelText, value string, labelY float64, valueColor color.Color) {
+	assets := s.accessor.Assets()
 
 	fieldWidth := 240.0
 	fieldHeight := 40.0
-	fieldX := float64(labelX) + float64(labelBounds.Dx()) + 10
+	fieldX := float64(s.accessor.Screen().Width)/2 - 20
 	fieldY := labelY - fieldHeight/2 - 5
 
+	labelBounds := text.BoundString(assets.UIFont, labelText)
+	labelX := int(fieldX) - 10 - labelBounds.Dx()
+	text.Draw(screen, labelText, assets.UIFont, labelX, int(labelY), color.White)
+
 	ui.DrawRectangle(screen, assets, fieldX-2, fieldY-2, fieldWidth+4, fieldHeight+4, color.Gray{Y: 128})
 	ui.DrawRectangle(screen, assets, fieldX, fieldY, fieldWidth, fieldHeight, color.Black)
 
-	var levelName string
-	if len(s.levelNames) > 0 {
-		levelName = strings.TrimSuffix(s.levelNames[s.currentLevel], ".json")
-	} else {
-		levelName = "Levels not found"
-	}
-	levelBounds := text.BoundString(assets.UIFont, levelName)
-	levelTextX := fieldX + (fieldWidth-float64(levelBounds.Dx()))/2
-	levelTextY := fieldY + (fieldHeight+float64(levelBounds.Dy()))/2
-	text.Draw(screen, levelName, assets.UIFont, int(levelTextX), int(levelTextY), color.White)
+	valueBounds := text.BoundString(assets.UIFont, value)
+	valueTextX := fieldX + (fieldWidth-float64(valueBounds.Dx()))/2
+	valueTextY := fieldY + (fieldHeight+float64(valueBounds.Dy()))/2
+	text.Draw(screen, value, assets.UIFont, int(valueTextX), int(valueTextY), valueColor)
 }
 
 func (s *MainMenuScene) Update() (core.GameState, error) {
+	s.updateDemo()
 
 	s.newGameButton.Update()
+	s.campaignButton.Update()
+	s.joinGameButton.Update()
 	s.createLevelButton.Update()
 	s.rankingButton.Update()
+	s.settingsButton.Update()
 	s.quitButton.Update()
 
 	s.handleInput()
@@ -124,18 +211,71 @@ func (s *MainMenuScene) Update() (core.GameState, error) {
 
 }
 
+// updateDemo продвигает фоновую партию ботов и начинает её заново, когда она заканчивается.
+func (s *MainMenuScene) updateDemo() {
+	if s.demoWorld == nil {
+		return
+	}
+	if s.demoWorld.IsOver() {
+		if err := s.demoWorld.Reset(rand.Uint64()); err != nil {
+			s.accessor.Logger().Error("failed to restart demo", "error", err)
+			s.demoWorld = nil
+		}
+		return
+	}
+
+	inputs := make([]sim.Input, len(s.demoBots))
+	for player, bot := range s.demoBots {
+		inputs[player] = ai.Input(bot, s.demoWorld, player)
+	}
+	s.demoWorld.Step(inputs...)
+}
+
+// startDemo начинает фоновую партию на пустом поле: экран, разбитый на клетки tile_size.
+func (s *MainMenuScene) startDemo() {
+	cfg := s.accessor.Config()
+	world, err := sim.NewWorld(s.demoLevel(), sim.RulesFromConfig(cfg), sim.ClassicMode{}, len(s.demoBots), rand.Uint64())
+	if err != nil {
+		s.accessor.Logger().Error("failed to start demo", "error", err)
+		return
+	}
+	s.demoWorld = world
+}
+
+// demoLevel — поле фоновой партии: сколько клеток tile_size помещается на экране под верхней панелью.
+func (s *MainMenuScene) demoLevel() *core.Level {
+	layout := s.accessor.Screen()
+	tileSize := s.accessor.Config().TileSize
+	return &core.Level{
+		Name:       "demo",
+		GridWidth:  max(layout.Width/tileSize, 1),
+		GridHeight: max((layout.Height-layout.TopBarHeight)/tileSize, 1),
+	}
+}
+
 func (s *MainMenuScene) handleInput() {
-	if len(s.levelNames) == 0 {
+	controls := s.accessor.Input()
+	direction, moved := controls.MenuDirection()
+	if moved && direction == core.Left {
+		s.currentMode = (s.currentMode + len(s.modes) - 1) % len(s.modes)
+	} else if moved && direction == core.Right {
+		s.currentMode = (s.currentMode + 1) % len(s.modes)
+	}
+	if controls.JustPressed(config.ActionLineup) {
+		s.currentLineup = (s.currentLineup + 1) % len(s.lineups)
+	}
+
+	if len(s.levels) == 0 {
 		return
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
+	if moved && direction == core.Up {
 		s.currentLevel--
 		if s.currentLevel < 0 {
-			s.currentLevel = len(s.levelNames) - 1
+			s.currentLevel = len(s.levels) - 1
 		}
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
-		s.currentLevel = (s.currentLevel + 1) % len(s.levelNames)
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+	} else if moved && direction == core.Down {
+		s.currentLevel = (s.currentLevel + 1) % len(s.levels)
+	} else if controls.JustPressed(config.ActionConfirm) {
 		s.newGame()
 	}
 }
@@ -143,65 +283,70 @@ func (s *MainMenuScene) handleInput() {
 func (s *MainMenuScene) OnEnter() {
 	s.accessor.Logger().Info("Entering main menu, scanning for levels...")
 
-	s.levelNames = []string{}
+	s.levels = nil
 	s.currentLevel = 0
 
-	if err := os.MkdirAll("levels", 0755); err != nil {
+	if err := os.MkdirAll(levels.Dir, 0755); err != nil {
 		s.accessor.Logger().Error("failed to create levels directory", "error", err)
 		return
 	}
 
-	err := filepath.WalkDir("levels", func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if !d.IsDir() && strings.HasSuffix(d.Name(), ".json") {
-			s.levelNames = append(s.levelNames, d.Name())
-		}
-		return nil
-	})
-
+	entries, err := levels.Scan(levels.Dir)
 	if err != nil {
 		s.accessor.Logger().Error("failed to scan for levels", "error", err)
 	}
+	for _, entry := range entries {
+		if !entry.IsValid() {
+			s.accessor.Logger().Warn("invalid level", "path", entry.Path, "error", entry.Err)
+		}
+	}
+	s.levels = entries
+
+	if s.demoWorld == nil {
+		s.startDemo()
+	}
 
 	s.nextState = core.MainMenuState
 }
 
 func (s *MainMenuScene) newGame() {
-	if len(s.levelNames) == 0 {
+	if len(s.levels) == 0 {
 		s.accessor.Logger().Warn("no levels were found")
 		return
 	}
 
-	levelFileName := s.levelNames[s.currentLevel]
-	levelPath := path.Join("levels", levelFileName)
-
-	s.accessor.Logger().Info("loading level", "path", levelPath)
-	data, err := os.ReadFile(levelPath)
-	if err != nil {
-		s.accessor.Logger().Error("failed to read level file", "path", levelPath, "error", err)
+	entry := s.levels[s.currentLevel]
+	chosen := s.lineups[s.currentLineup]
+	if err := entry.ErrFor(chosen.players); err != nil {
+		s.accessor.Logger().Warn("can't start invalid level", "path", entry.Path, "players", chosen.players, "error", err)
 		return
 	}
+	level := entry.Level
+	s.accessor.Logger().Info("loading level", "path", entry.Path)
 
-	var level core.Level
-	if err := json.Unmarshal(data, &level); err != nil {
-		s.accessor.Logger().Error("failed to parse level json", "path", levelPath, "error", err)
-		return
-	}
-
-	if level.GridHeight > s.accessor.Config().ScreenHeight/s.accessor.Config().TileSize ||
-		level.GridWidth > s.accessor.Config().ScreenWidth/s.accessor.Config().TileSize {
-		s.accessor.Logger().Warn("unappropriated level size",
-			"grid_width", level.GridWidth,
-			"grid_height", level.GridHeight,
-			"cfg_max_width", s.accessor.Config().ScreenHeight/s.accessor.Config().TileSize,
-			"cfg_max_height", s.accessor.Config().ScreenWidth/s.accessor.Config().TileSize)
-		return
+	var bots []ai.Controller
+	if chosen.bot != nil {
+		bots = make([]ai.Controller, chosen.players)
+		bots[chosen.players-1] = chosen.bot
 	}
 
 	s.nextState = core.GamePlayingState
-	s.accessor.StartGame(&level)
+	s.accessor.StartGame(GameSetup{
+		Level:   level,
+		Mode:    s.modes[s.currentMode],
+		Players: chosen.players,
+		Bots:    bots,
+	})
+}
+
+func (s *MainMenuScene) campaign() {
+	s.accessor.Logger().Info("go to campaignScene")
+	s.nextState = core.CampaignState
+}
+
+func (s *MainMenuScene) joinGame() {
+	s.accessor.Logger().Info("go to joinScene")
+	s.nextState = core.JoinGameState
 }
 
 func (s *MainMenuScene) createLevel() {
@@ -213,3 +358,9 @@ func (s *MainMenuScene) ranking() {
 	s.accessor.Logger().Info("go to rankingScene")
 	s.nextState = core.BestScoresState
 }
+
+func (s *MainMenuScene) settings() {
+	s.accessor.Logger().Info("go to settingsScene")
+	s.accessor.OpenSettings(core.MainMenuState)
+	s.nextState = core.SettingsState
+}
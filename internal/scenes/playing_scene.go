@@ -3,313 +3,277 @@ package scenes
 import (
 	"fmt"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text"
 	"image/color"
 	"math/rand/v2"
+	"snake-game/internal/ai"
+	"snake-game/internal/audio"
+	"snake-game/internal/config"
 	"snake-game/internal/core"
+	"snake-game/internal/replay"
+	"snake-game/internal/sim"
 	"snake-game/internal/ui"
+	"strings"
 )
 
 type PlayingScene struct {
-	snake *core.Snake
-	food  *core.Food
-	walls []core.Wall
+	world  *sim.World
+	replay *replay.Replay
 
-	level *core.Level
+	level   *core.Level
+	mode    sim.GameMode
+	players int
+	setup   GameSetup
 
 	whitePixelImage *ebiten.Image
 
+	// paused — игра стоит: мир не продвигается, время игры не идёт, поверх поля меню паузы
+	paused         bool
+	resumeButton   *ui.Button
+	restartButton  *ui.Button
+	settingsButton *ui.Button
+	quitButton     *ui.Button
+	// pauseState — куда перейти по кнопке меню паузы
+	pauseState core.GameState
+
 	accessor GameAccessor
 }
 
-func NewPlayingScene(accessor GameAccessor, level *core.Level) (*PlayingScene, error) {
+func NewPlayingScene(accessor GameAccessor, setup GameSetup) (*PlayingScene, error) {
 	scene := &PlayingScene{
 		accessor: accessor,
-		level:    level,
-		walls:    level.Walls,
+		level:    setup.Level,
+		mode:     setup.Mode,
+		players:  setup.Players,
+		setup:    setup,
 	}
 
 	err := scene.Reset()
 	if err != nil {
 		return nil, err
 	}
+	scene.Relayout()
 	return scene, nil
 }
 
+// Relayout раскладывает меню паузы по центру экрана.
+func (p *PlayingScene) Relayout() {
+	layout := p.accessor.Screen()
+	centerX := float64(layout.Width) / 2
+	startY := float64(layout.Height)/2 - 40
+
+	p.resumeButton = ui.NewButton(centerX-120, startY, 240, 50, "RESUME", p.resume)
+	p.restartButton = ui.NewButton(centerX-120, startY+60, 240, 50, "RESTART", func() {
+		p.restart()
+		p.resume()
+	})
+	p.settingsButton = ui.NewButton(centerX-120, startY+120, 240, 50, "SETTINGS", func() {
+		p.accessor.OpenSettings(core.GamePlayingState)
+		p.pauseState = core.SettingsState
+	})
+	quitText := "QUIT TO MENU"
+	if p.setup.PlayTest {
+		quitText = "BACK TO EDITOR"
+	}
+	p.quitButton = ui.NewButton(centerX-120, startY+180, 240, 50, quitText, func() {
+		p.accessor.Logger().Info("game quit from pause menu")
+		p.pauseState = p.setup.ReturnState
+	})
+}
+
+// IsPaused — стоит ли игра на паузе; пока она стоит, время игры не идёт.
+func (p *PlayingScene) IsPaused() bool {
+	return p.paused
+}
+
+func (p *PlayingScene) pause() {
+	p.accessor.Logger().Info("game paused")
+	p.paused = true
+}
+
+func (p *PlayingScene) resume() {
+	p.accessor.Logger().Info("game resumed")
+	p.paused = false
+}
+
+// restart начинает игру заново на том же уровне: с новым зерном, если оно не задано в GameSetup.
+func (p *PlayingScene) restart() {
+	if err := p.Reset(); err != nil {
+		p.accessor.Logger().Error("failed to reset game", "error", err)
+		return
+	}
+	if err := p.accessor.Reset(); err != nil {
+		p.accessor.Logger().Error("failed to reset game", "error", err)
+	}
+}
+
 func (p *PlayingScene) Reset() error {
 	p.accessor.Logger().Info("playing scene  resetting...")
 	cfg := p.accessor.Config()
-	level := p.level
-	var err error
 
-	startPos := core.Position{
-		X: level.GridWidth / 2,
-		Y: level.GridHeight / 2,
+	seed := p.setup.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
 	}
-
-	snake, err := core.NewSnake(startPos.X, startPos.Y, cfg.InitialSnakeLen, cfg.InitialSpeed, cfg.MaxSpeed)
+	world, err := sim.NewWorld(p.level, sim.RulesFromConfig(cfg), p.mode, p.players, seed)
 	if err != nil {
-		p.accessor.Logger().Error("FATAL: failed to create snake during reset", "error", err)
-		return fmt.Errorf("не удалось создать змею: %w", err)
+		p.accessor.Logger().Error("FATAL: failed to create world during reset", "error", err)
+		return fmt.Errorf("не удалось создать игровое поле: %w", err)
 	}
 
-	p.accessor.Logger().Info("snake created successfully")
-	p.snake = snake
-	p.spawnFood()
+	p.accessor.Logger().Info("world created successfully", "seed", world.Seed())
+	p.world = world
+	p.replay = replay.New(p.level, world.Rules(), p.mode, p.players, world.Seed())
 	return nil
 }
 
-func (p *PlayingScene) spawnFood() {
-	occupiedCells := make(map[core.Position]bool)
-	for _, wall := range p.walls {
-		occupiedCells[wall.Position] = true
+func (p *PlayingScene) Update() (core.GameState, error) {
+	p.accessor.Logger().Info("updating playing scene")
+	if p.world.IsOver() {
+		return p.finishedState(), nil
+	}
+	controls := p.accessor.Input()
+	if p.setup.PlayTest && controls.JustPressed(config.ActionBack) {
+		p.accessor.Logger().Info("play test stopped")
+		return p.setup.ReturnState, nil
 	}
-	for _, snakePart := range p.snake.Body {
-		occupiedCells[snakePart.Position] = true
+	if p.paused {
+		return p.updatePaused(), nil
+	}
+	// при потере фокуса окна игра встаёт на паузу сама
+	if !ebiten.IsFocused() || controls.JustPressed(config.ActionPause) || controls.JustPressed(config.ActionBack) {
+		p.pause()
+		return core.GamePlayingState, nil
 	}
 
-	freeCells := make([]core.Position, 0)
-	maxWidth := p.level.GridWidth
-	maxHeight := p.level.GridHeight
-	for i := 0; i < maxWidth; i++ {
-		for j := 0; j < maxHeight; j++ {
-			if (occupiedCells[core.Position{X: i, Y: j}] == false) {
-				freeCells = append(freeCells, core.Position{X: i, Y: j})
-			}
+	sounds := p.accessor.Audio()
+	inputs := p.handleInput()
+	for player, input := range inputs {
+		p.replay.RecordInput(p.world.Tick, player, input)
+		// повороты ботов не озвучиваются, чтобы не заглушать игрока
+		if len(input.Turns) > 0 && p.setup.bot(player) == nil {
+			sounds.Play(audio.SoundTurn)
 		}
 	}
 
-	if len(freeCells) == 0 {
-		p.accessor.Logger().Warn("failed to created food: no free space left")
-	} else {
-		randomIndex := rand.IntN(len(freeCells))
-		p.food = core.NewFood(freeCells[randomIndex].X, freeCells[randomIndex].Y)
-		p.accessor.Logger().Info("new food created", "position X", freeCells[randomIndex].X, "position Y", freeCells[randomIndex].Y)
+	logger := p.accessor.Logger()
+	events := p.world.Step(inputs...)
+	if events.AteFood {
+		logger.Info("snake ate food")
+		sounds.Play(audio.SoundEat)
 	}
-}
-
-func (p *PlayingScene) Update() (core.GameState, error) {
-	p.accessor.Logger().Info("updating playing scene")
-	if !p.snake.IsAlive {
-		return core.GameOverState, nil
+	for _, player := range events.Scored {
+		p.accessor.NotifyScored(player)
 	}
-
-	p.handleInput()
-
-	moved := p.snake.Update()
-	if moved {
-		state, err := p.checkCollisions()
-		if err != nil {
-			return 0, err
+	if events.SpeedUp {
+		logger.Info("change snake speed")
+		sounds.Play(audio.SoundSpeedUp)
+	}
+	if events.Died {
+		sounds.Play(audio.SoundDeath)
+		for player, cause := range p.world.Causes {
+			if cause != sim.NotDead {
+				logger.Info("snake crashed", "player", player, "cause", cause.String())
+			}
 		}
-		return state, nil
+	}
+	if p.world.IsOver() {
+		logger.Info("game over", "reason", p.world.DeathCause.String())
+		p.replay.Finish(p.world)
+		p.accessor.FinishGame(p.replay)
+		return p.finishedState(), nil
 	}
 	return core.GamePlayingState, nil
 }
 
-func (p *PlayingScene) handleInput() {
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
-		p.snake.SetNextDirection(core.Up)
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
-		p.snake.SetNextDirection(core.Down)
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) {
-		p.snake.SetNextDirection(core.Left)
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) {
-		p.snake.SetNextDirection(core.Right)
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyR) {
-		err := p.Reset()
-		if err != nil {
-			p.accessor.Logger().Error("failed to reset game", err)
-			return
-		}
-		err = p.accessor.Reset()
-		if err != nil {
-			p.accessor.Logger().Error("failed to reset game", err)
-		}
+func (p *PlayingScene) updatePaused() core.GameState {
+	controls := p.accessor.Input()
+	if controls.JustPressed(config.ActionPause) || controls.JustPressed(config.ActionBack) {
+		p.resume()
+		return core.GamePlayingState
 	}
+	p.resumeButton.Update()
+	p.restartButton.Update()
+	p.settingsButton.Update()
+	p.quitButton.Update()
+	return p.pauseState
 }
 
-func (p *PlayingScene) checkCollisions() (core.GameState, error) {
-	logger := p.accessor.Logger()
-	logger.Info("checking collisions")
-	cfg := p.accessor.Config()
-	level := p.level
-
-	for _, wall := range p.walls {
-		if wall.Position == p.snake.Body[0].Position {
-			p.snake.IsAlive = false
-			logger.Info("snake crashed in wall")
-			break
-		}
-	}
-
-	if p.snake.Body[0].X < 0 || p.snake.Body[0].X >= level.GridWidth {
-		p.snake.IsAlive = false
-		logger.Info("snake crashed in border")
-	}
-	if p.snake.Body[0].Y < 0 || p.snake.Body[0].Y >= level.GridHeight {
-		p.snake.IsAlive = false
-		logger.Info("snake crashed in border")
+// finishedState — куда переходить после конца игры: пробная игра сразу возвращается в редактор.
+func (p *PlayingScene) finishedState() core.GameState {
+	if p.setup.PlayTest {
+		return p.setup.ReturnState
 	}
+	return core.GameOverState
+}
 
-	if p.food.Position == p.snake.Body[0].Position {
-		logger.Info("snake ate food")
-		needSpeedIncrease := p.accessor.NotifyFoodEaten()
-
-		if needSpeedIncrease {
-			p.snake.DecreaseMoveInterval(cfg.SpeedIncreaseAmount)
-			logger.Info("change snake speed")
-		}
-
-		p.spawnFood()
-	} else {
-		err := p.snake.CutTail()
-		if err != nil {
-			return 0, err
+func (p *PlayingScene) handleInput() []sim.Input {
+	controls := p.accessor.Input()
+	inputs := make([]sim.Input, p.players)
+	humans := 0
+	for player := range inputs {
+		if bot := p.setup.bot(player); bot != nil {
+			inputs[player] = ai.Input(bot, p.world, player)
+			continue
 		}
-	}
 
-	p.snake.CheckCollisionsWithSelf()
-	if p.snake.IsAlive == false {
-		logger.Info("snake crashed in its body")
+		// клавиши и геймпады раздаются по порядку только людям: против бота первый игрок
+		// играет клавишами первого игрока
+		inputs[player].Turns = controls.Turns(humans)
+		humans++
 	}
 
-	if p.snake.IsAlive == false {
-		return core.GameOverState, nil
+	if controls.JustPressed(config.ActionFinish) {
+		inputs[0].Finish = true
+	} else if controls.JustPressed(config.ActionRestart) {
+		p.restart()
+		return nil
 	}
-
-	return core.GamePlayingState, nil
+	return inputs
 }
 
 func (p *PlayingScene) Draw(screen *ebiten.Image) {
-	cfg := p.accessor.Config()
-	assets := p.accessor.Assets()
-
 	screen.Fill(color.RGBA{R: 5, G: 5, B: 15, A: 255})
 
-	fieldWidthInPixels := float64(p.level.GridWidth * cfg.TileSize)
-	fieldHeightInPixels := float64(p.level.GridHeight * cfg.TileSize)
-
-	topBarHeight := float64(cfg.TopBarHeight)
-	ui.DrawRectangle(
-		screen,
-		assets,
-		0,
-		topBarHeight,
-		fieldWidthInPixels,
-		fieldHeightInPixels,
-		color.NRGBA{R: 0x10, G: 0x10, B: 0x10, A: 0xff},
-	)
-
-	opBar := &ebiten.DrawImageOptions{}
-	opBar.GeoM.Scale(float64(cfg.ScreenWidth), topBarHeight)
-	screen.DrawImage(assets.WhitePixel, opBar)
-
-	scoreStr := fmt.Sprintf("SCORE: %d", p.accessor.Score())
-	seconds := int(p.accessor.GameTime().Seconds())
-	timeStr := fmt.Sprintf("TIME: %02d:%02d", seconds/60, seconds%60)
-
-	text.Draw(screen, scoreStr, assets.UIFont, 10, 25, color.Black)
-	text.Draw(screen, timeStr, assets.UIFont, cfg.ScreenWidth-200, 25, color.Black)
-
-	if p.snake.IsAlive {
-		p.drawSnake(screen)
+	scoreStr := scoresText(p.players, p.accessor.Score)
+	var timeStr string
+	if timeAttack, ok := p.mode.(sim.TimeAttackMode); ok {
+		seconds := timeAttack.TicksLeft(p.world) / sim.TicksPerSecond
+		timeStr = fmt.Sprintf("LEFT: %02d:%02d", seconds/60, seconds%60)
+	} else {
+		seconds := int(p.accessor.GameTime().Seconds())
+		timeStr = fmt.Sprintf("TIME: %02d:%02d", seconds/60, seconds%60)
 	}
 
-	p.drawFood(screen)
-	p.drawWalls(screen)
-}
+	drawWorld(screen, p.accessor, p.world)
+	drawTopBar(screen, p.accessor, scoreStr, strings.ToUpper(p.mode.Name()), timeStr)
 
-func (p *PlayingScene) drawSnake(screen *ebiten.Image) {
-	assets := p.accessor.Assets()
-	cfg := p.accessor.Config()
-
-	for i, segment := range p.snake.Body {
-
-		var (
-			img      *ebiten.Image
-			rotation float64
-		)
-
-		if i == 0 {
-			img = assets.SnakeHead
-			rotation = core.DirectionToRotationAngle(p.snake.Direction)
-
-		} else if i == len(p.snake.Body)-1 {
-			img = assets.SnakeTail
-			direction := core.GetDirection(p.snake.Body[i-1].Position, segment.Position)
-			rotation = core.DirectionToRotationAngle(direction)
-
-		} else {
-			newDirection := core.GetDirection(p.snake.Body[i-1].Position, segment.Position)
-			oldDirection := core.GetDirection(segment.Position, p.snake.Body[i+1].Position)
-			if newDirection == oldDirection {
-				img = assets.SnakeBody
-				rotation = core.DirectionToRotationAngle(oldDirection)
-			} else {
-				img = assets.SnakeBodyCorner
-				rotation = core.CornerToRotationAngle(oldDirection, newDirection)
-			}
-		}
-
-		if img != nil {
-			op := &ebiten.DrawImageOptions{}
-
-			originalWidth := img.Bounds().Dx()
-			originalHeight := img.Bounds().Dy()
-
-			var scaleX, scaleY float64
-			if originalWidth > 0 {
-				scaleX = float64(cfg.TileSize) / float64(originalWidth)
-			}
-			if originalHeight > 0 {
-				scaleY = float64(cfg.TileSize) / float64(originalHeight)
-			}
-
-			op.GeoM.Scale(scaleX, scaleY)
-			op.GeoM.Translate(-float64(cfg.TileSize)/2, -float64(cfg.TileSize)/2)
-
-			op.GeoM.Rotate(rotation)
-
-			op.GeoM.Translate(float64(segment.X*cfg.TileSize)+float64(cfg.TileSize)/2, float64(segment.Y*cfg.TileSize)+float64(cfg.TileSize)/2+float64(cfg.TopBarHeight))
-
-			screen.DrawImage(img, op)
-		}
+	if p.paused {
+		p.drawPauseMenu(screen)
 	}
 }
 
-func (p *PlayingScene) drawFood(screen *ebiten.Image) {
-	cfg := p.accessor.Config()
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(p.food.X*cfg.TileSize), float64(p.food.Y*cfg.TileSize)+float64(cfg.TopBarHeight))
-	img := p.accessor.Assets().Apple
-	screen.DrawImage(img, op)
-}
-
-func (p *PlayingScene) drawWalls(screen *ebiten.Image) {
-	cfg := p.accessor.Config()
-	for _, wall := range p.walls {
-		op := &ebiten.DrawImageOptions{}
-		img := p.accessor.Assets().Wall
+func (p *PlayingScene) drawPauseMenu(screen *ebiten.Image) {
+	layout := p.accessor.Screen()
+	assets := p.accessor.Assets()
 
-		originalWidth := img.Bounds().Dx()
-		originalHeight := img.Bounds().Dy()
+	opOverlay := &ebiten.DrawImageOptions{}
+	opOverlay.GeoM.Scale(float64(layout.Width), float64(layout.Height))
+	opOverlay.ColorScale.Scale(0, 0, 0, 0.66)
+	screen.DrawImage(assets.WhitePixel, opOverlay)
 
-		var scaleX, scaleY float64
-		if originalWidth > 0 {
-			scaleX = float64(cfg.TileSize) / float64(originalWidth)
-		}
-		if originalHeight > 0 {
-			scaleY = float64(cfg.TileSize) / float64(originalHeight)
-		}
+	pausedText := "PAUSED"
+	pausedBounds := text.BoundString(assets.TitleFont, pausedText)
+	text.Draw(screen, pausedText, assets.TitleFont, (layout.Width-pausedBounds.Dx())/2, int(p.resumeButton.Y)-40, color.White)
 
-		op.GeoM.Scale(scaleX, scaleY)
-		op.GeoM.Translate(float64(wall.X*cfg.TileSize), float64(wall.Y*cfg.TileSize)+float64(cfg.TopBarHeight))
-		screen.DrawImage(img, op)
-	}
+	p.resumeButton.Draw(screen, assets)
+	p.restartButton.Draw(screen, assets)
+	p.settingsButton.Draw(screen, assets)
+	p.quitButton.Draw(screen, assets)
 }
 
 func (p *PlayingScene) OnEnter() {
-	p.accessor.Logger().Info("Entering playing scene", "level", p.level.Name)
+	p.accessor.Logger().Info("Entering playing scene", "level", p.level.Name, "mode", p.mode.Name())
+	// после экрана настроек игра остаётся на паузе
+	p.pauseState = core.GamePlayingState
 }
@@ -8,7 +8,10 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/text"
 	"image"
 	"image/color"
+	"snake-game/internal/config"
 	"snake-game/internal/core"
+	"snake-game/internal/replay"
+	"snake-game/internal/sim"
 	"snake-game/internal/storage"
 	"snake-game/internal/ui"
 	"time"
@@ -16,6 +19,16 @@ import (
 
 const (
 	RecordsNumber = 20
+
+	rankingHeaderY   = 220
+	rankingRowHeight = 30
+	// rankingTableWidth — ширина таблицы вместе с полями фильтров над ней; таблица стоит
+	// по центру экрана, а колонки отсчитываются от её левого края
+	rankingTableWidth = 1200
+	rankingColScore   = 260
+	rankingColTime    = 370
+	rankingColMode    = 860
+	rankingColReplay  = 1090
 )
 
 type RankingScene struct {
@@ -31,13 +44,17 @@ type RankingScene struct {
 
 	isScoreAsc bool
 	isTimeAsc  bool
+	// modeIndex — индекс выбранного режима в sim.Modes(), -1 — все режимы
+	modeIndex int
 
 	activeField   string
 	cursorVisible bool
 	cursorBlink   time.Time
 
-	scoreButton *ui.Button
-	timeButton  *ui.Button
+	scoreButton   *ui.Button
+	timeButton    *ui.Button
+	modeButton    *ui.Button
+	replayButtons []*ui.Button
 
 	playerNameFieldRect image.Rectangle
 	levelNameFieldRect  image.Rectangle
@@ -48,59 +65,88 @@ func NewRankingScene(accessor GameAccessor) *RankingScene {
 		accessor: accessor,
 	}
 
+	scene.Relayout()
+	scene.reset()
+	scene.loadRecords()
+
+	return scene
+}
+
+// Relayout ставит поля фильтров и кнопки сортировки над таблицей по центру экрана.
+func (r *RankingScene) Relayout() {
 	fieldsY := 120 // Y-координата для полей ввода
 
 	// --- 2. Инициализация полей ввода ---
 	fieldWidth := 300
 	fieldHeight := 40
 
-	playerFieldX := 40
-	scene.playerNameFieldRect = image.Rect(playerFieldX, fieldsY, playerFieldX+fieldWidth, fieldsY+fieldHeight)
+	playerFieldX := r.tableX()
+	r.playerNameFieldRect = image.Rect(playerFieldX, fieldsY, playerFieldX+fieldWidth, fieldsY+fieldHeight)
 
 	levelFieldX := playerFieldX + fieldWidth + 20
-	scene.levelNameFieldRect = image.Rect(levelFieldX, fieldsY, levelFieldX+fieldWidth, fieldsY+fieldHeight)
+	r.levelNameFieldRect = image.Rect(levelFieldX, fieldsY, levelFieldX+fieldWidth, fieldsY+fieldHeight)
+
+	modeFieldX := levelFieldX + fieldWidth + 20
+	r.modeButton = ui.NewButton(
+		float64(modeFieldX),
+		float64(fieldsY),
+		float64(fieldWidth),
+		float64(fieldHeight),
+		r.modeLabel(),
+		func() {
+			r.modeIndex++
+			if r.modeIndex >= len(sim.Modes()) {
+				r.modeIndex = -1
+			}
+			r.modeButton.Text = r.modeLabel()
+			r.loadRecords()
+		},
+	)
 
-	colX_Score := 300
-	colX_Time := 410
-	scoreTextWidth := text.BoundString(accessor.Assets().UIFont, "SCORE").Dx()
-	timeTextWidth := text.BoundString(accessor.Assets().UIFont, "TIME").Dx()
+	left := r.tableX()
+	scoreTextWidth := text.BoundString(r.accessor.Assets().UIFont, "SCORE").Dx()
+	timeTextWidth := text.BoundString(r.accessor.Assets().UIFont, "TIME").Dx()
 	buttonSize := 25
 	buttonY := float64(195)
 
-	scene.scoreButton = ui.NewButton(
-		float64(colX_Score+scoreTextWidth+5),
+	r.scoreButton = ui.NewButton(
+		float64(left+rankingColScore+scoreTextWidth+5),
 		buttonY,
 		float64(buttonSize),
 		float64(buttonSize),
 		"F",
 		func() {
-			scene.isScoreAsc = !scene.isScoreAsc
-			scene.loadRecords()
+			r.isScoreAsc = !r.isScoreAsc
+			r.loadRecords()
 		},
 	)
 
-	scene.timeButton = ui.NewButton(
-		float64(colX_Time+timeTextWidth+5),
+	r.timeButton = ui.NewButton(
+		float64(left+rankingColTime+timeTextWidth+5),
 		buttonY,
 		float64(buttonSize),
 		float64(buttonSize),
 		"F",
 		func() {
-			scene.isTimeAsc = !scene.isTimeAsc
-			scene.loadRecords()
+			r.isTimeAsc = !r.isTimeAsc
+			r.loadRecords()
 		},
 	)
 
-	scene.reset()
-	scene.loadRecords()
+	r.createReplayButtons()
+}
 
-	return scene
+// tableX — левый край таблицы, стоящей по центру экрана.
+func (r *RankingScene) tableX() int {
+	return max((r.accessor.Screen().Width-rankingTableWidth)/2, 0)
 }
 
 func (r *RankingScene) reset() {
 	r.nextState = core.BestScoresState
 	r.isScoreAsc = false
 	r.isTimeAsc = true
+	r.modeIndex = -1
+	r.modeButton.Text = r.modeLabel()
 	r.loadError = nil
 	r.records = make([]storage.Record, 0)
 	r.levelName = make([]rune, 0)
@@ -108,9 +154,23 @@ func (r *RankingScene) reset() {
 	r.activeField = ""
 }
 
+func (r *RankingScene) modeFilter() string {
+	if r.modeIndex < 0 {
+		return ""
+	}
+	return sim.Modes()[r.modeIndex].Name()
+}
+
+func (r *RankingScene) modeLabel() string {
+	if r.modeIndex < 0 {
+		return "ALL"
+	}
+	return r.modeFilter()
+}
+
 func (r *RankingScene) loadRecords() {
 	repo := r.accessor.Repository()
-	filter := storage.NewFilter(string(r.playerName), string(r.levelName), r.isScoreAsc, r.isTimeAsc, RecordsNumber)
+	filter := storage.NewFilter(string(r.playerName), string(r.levelName), r.modeFilter(), r.isScoreAsc, r.isTimeAsc, RecordsNumber)
 	var err error
 	r.records, err = repo.GetTopRecords(context.Background(), *filter)
 	if err != nil {
@@ -119,42 +179,73 @@ func (r *RankingScene) loadRecords() {
 	} else {
 		r.loadError = nil
 	}
+	r.createReplayButtons()
+}
+
+func (r *RankingScene) createReplayButtons() {
+	r.replayButtons = make([]*ui.Button, 0, len(r.records))
+	for i, record := range r.records {
+		if record.ReplayFile == "" {
+			continue
+		}
+		replayFile := record.ReplayFile
+		rowY := rankingHeaderY + (i+1)*rankingRowHeight
+		button := ui.NewButton(float64(r.tableX()+rankingColReplay), float64(rowY-20), 25, 25, ">", func() {
+			r.watchReplay(replayFile)
+		})
+		r.replayButtons = append(r.replayButtons, button)
+	}
+}
+
+func (r *RankingScene) watchReplay(replayFile string) {
+	rep, err := replay.Load(replayFile)
+	if err != nil {
+		r.accessor.Logger().Error("failed to load replay", "path", replayFile, "error", err)
+		return
+	}
+	r.accessor.WatchReplay(rep, core.BestScoresState)
+	r.nextState = core.ReplayState
 }
 
 func (r *RankingScene) Draw(screen *ebiten.Image) {
 
 	screen.Fill(color.NRGBA{R: 0x0A, G: 0x19, B: 0x4E, A: 0xff})
 
-	cfg := r.accessor.Config()
+	layout := r.accessor.Screen()
 	uiFont := r.accessor.Assets().UIFont
 	titleFont := r.accessor.Assets().TitleFont
 
 	title := "TOP SCORES"
 	titleBounds := text.BoundString(titleFont, title)
-	titleX := (cfg.ScreenWidth - titleBounds.Dx()) / 2
+	titleX := (layout.Width - titleBounds.Dx()) / 2
 	text.Draw(screen, title, titleFont, titleX, 60, color.White)
 
 	if r.loadError != nil {
 		errorMsg := fmt.Sprintf("Error: Could not load records.")
 		errorBounds := text.BoundString(uiFont, errorMsg)
-		errorX := (cfg.ScreenWidth - errorBounds.Dx()) / 2
-		text.Draw(screen, errorMsg, uiFont, errorX, cfg.ScreenHeight/2, color.RGBA{R: 255, G: 100, B: 100, A: 255})
+		errorX := (layout.Width - errorBounds.Dx()) / 2
+		text.Draw(screen, errorMsg, uiFont, errorX, layout.Height/2, color.RGBA{R: 255, G: 100, B: 100, A: 255})
 		return
 	}
 
 	text.Draw(screen, "Player Name:", uiFont, r.playerNameFieldRect.Min.X, r.playerNameFieldRect.Min.Y-10, color.White)
 	text.Draw(screen, "Level Name:", uiFont, r.levelNameFieldRect.Min.X, r.levelNameFieldRect.Min.Y-10, color.White)
+	text.Draw(screen, "Mode:", uiFont, int(r.modeButton.X), int(r.modeButton.Y)-10, color.White)
+	r.modeButton.Draw(screen, r.accessor.Assets())
 	// Сами поля
 	r.drawInputField(screen, string(r.playerName), r.playerNameFieldRect, "player")
 	r.drawInputField(screen, string(r.levelName), r.levelNameFieldRect, "level")
 
-	headerY := 220
-	colX_Num := 40
-	colX_Player := 90
-	colX_Score := 300
-	colX_Time := 410
-	colX_Level := 510
-	colX_Date := 710
+	headerY := rankingHeaderY
+	left := r.tableX()
+	colX_Num := left
+	colX_Player := left + 50
+	colX_Score := left + rankingColScore
+	colX_Time := left + rankingColTime
+	colX_Level := left + 470
+	colX_Date := left + 670
+	colX_Mode := left + rankingColMode
+	colX_Replay := left + rankingColReplay
 
 	text.Draw(screen, "№", uiFont, colX_Num, headerY, color.White)
 	text.Draw(screen, "PLAYER", uiFont, colX_Player, headerY, color.White)
@@ -162,6 +253,8 @@ func (r *RankingScene) Draw(screen *ebiten.Image) {
 	text.Draw(screen, "TIME", uiFont, colX_Time, headerY, color.White)
 	text.Draw(screen, "LEVEL", uiFont, colX_Level, headerY, color.White)
 	text.Draw(screen, "DATE", uiFont, colX_Date, headerY, color.White)
+	text.Draw(screen, "MODE", uiFont, colX_Mode, headerY, color.White)
+	text.Draw(screen, "REPLAY", uiFont, colX_Replay, headerY, color.White)
 
 	r.scoreButton.Draw(screen, r.accessor.Assets())
 	r.timeButton.Draw(screen, r.accessor.Assets())
@@ -169,11 +262,11 @@ func (r *RankingScene) Draw(screen *ebiten.Image) {
 	if len(r.records) == 0 {
 		noRecordsMsg := "No records yet. Be the first!"
 		noRecordsBounds := text.BoundString(uiFont, noRecordsMsg)
-		noRecordsX := (cfg.ScreenWidth - noRecordsBounds.Dx()) / 2
+		noRecordsX := (layout.Width - noRecordsBounds.Dx()) / 2
 		text.Draw(screen, noRecordsMsg, uiFont, noRecordsX, headerY+60, color.Gray{Y: 180})
 	} else {
 		for i, record := range r.records {
-			rowY := headerY + (i+1)*30
+			rowY := headerY + (i+1)*rankingRowHeight
 			// #
 			text.Draw(screen, fmt.Sprintf("%d.", i+1), uiFont, colX_Num, rowY, color.White)
 			// PLAYER
@@ -187,12 +280,17 @@ func (r *RankingScene) Draw(screen *ebiten.Image) {
 			text.Draw(screen, record.LevelName, uiFont, colX_Level, rowY, color.White)
 			// DATE (в формате ГГГГ-ММ-ДД)
 			text.Draw(screen, record.CreatedAt.Format("2006-01-02"), uiFont, colX_Date, rowY, color.White)
+			// MODE
+			text.Draw(screen, record.Mode, uiFont, colX_Mode, rowY, color.White)
+		}
+		for _, button := range r.replayButtons {
+			button.Draw(screen, r.accessor.Assets())
 		}
 	}
 
-	exitMsg := "Press ESC to return to menu"
+	exitMsg := "Press " + keyHint(r.accessor.Input(), config.ActionBack) + " to return to menu"
 	exitBounds := text.BoundString(uiFont, exitMsg)
-	text.Draw(screen, exitMsg, uiFont, (cfg.ScreenWidth-exitBounds.Dx())/2, cfg.ScreenHeight-40, color.White)
+	text.Draw(screen, exitMsg, uiFont, (layout.Width-exitBounds.Dx())/2, layout.Height-40, color.White)
 }
 
 func (r *RankingScene) drawInputField(screen *ebiten.Image, content string, rect image.Rectangle, fieldName string) {
@@ -258,13 +356,17 @@ func (r *RankingScene) Update() (core.GameState, error) {
 		r.cursorBlink = time.Now()
 	}
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+	if r.accessor.Input().JustPressed(config.ActionBack) {
 		return core.MainMenuState, nil
 	}
 
 	r.scoreButton.Update()
 	r.timeButton.Update()
-	return core.BestScoresState, nil
+	r.modeButton.Update()
+	for _, button := range r.replayButtons {
+		button.Update()
+	}
+	return r.nextState, nil
 }
 
 func (r *RankingScene) setActiveField() {
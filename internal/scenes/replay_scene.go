@@ -0,0 +1,125 @@
+package scenes
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"image/color"
+	"snake-game/internal/config"
+	"snake-game/internal/core"
+	"snake-game/internal/replay"
+	"snake-game/internal/sim"
+	"strings"
+)
+
+const (
+	ReplayFastForward = 4
+)
+
+type ReplayScene struct {
+	accessor GameAccessor
+
+	replay *replay.Replay
+	player *replay.Player
+	world  *sim.World
+
+	isPaused    bool
+	returnState core.GameState
+	nextState   core.GameState
+}
+
+func NewReplayScene(accessor GameAccessor, rep *replay.Replay, returnState core.GameState) (*ReplayScene, error) {
+	scene := &ReplayScene{
+		accessor:    accessor,
+		replay:      rep,
+		returnState: returnState,
+	}
+
+	if err := scene.restart(); err != nil {
+		return nil, err
+	}
+	return scene, nil
+}
+
+func (r *ReplayScene) restart() error {
+	world, err := r.replay.NewWorld()
+	if err != nil {
+		return fmt.Errorf("не удалось воспроизвести повтор: %w", err)
+	}
+	r.world = world
+	r.player = replay.NewPlayer(r.replay)
+	r.isPaused = false
+	return nil
+}
+
+func (r *ReplayScene) Update() (core.GameState, error) {
+	controls := r.accessor.Input()
+	if controls.JustPressed(config.ActionBack) {
+		r.nextState = r.returnState
+		return r.nextState, nil
+	}
+	if controls.JustPressed(config.ActionPause) {
+		r.isPaused = !r.isPaused
+	}
+	if r.world.IsOver() && controls.JustPressed(config.ActionConfirm) {
+		if err := r.restart(); err != nil {
+			return 0, err
+		}
+	}
+
+	if r.isPaused {
+		return r.nextState, nil
+	}
+
+	steps := 1
+	if controls.Pressed(config.ActionP1Right) {
+		steps = ReplayFastForward
+	}
+	for i := 0; i < steps && !r.world.IsOver(); i++ {
+		r.world.Step(r.player.Inputs(r.world.Tick)...)
+		if r.world.IsOver() {
+			r.accessor.Logger().Info("replay finished",
+				"scores", r.world.Scores,
+				"recorded_scores", r.replay.Scores,
+				"reason", r.world.DeathCause.String())
+		}
+	}
+
+	return r.nextState, nil
+}
+
+func (r *ReplayScene) Draw(screen *ebiten.Image) {
+	layout := r.accessor.Screen()
+	assets := r.accessor.Assets()
+
+	screen.Fill(color.RGBA{R: 5, G: 5, B: 15, A: 255})
+
+	scoreStr := "REPLAY  " + scoresText(r.world.Players(), func(player int) int {
+		return r.world.Scores[player]
+	})
+	seconds := r.world.Tick / sim.TicksPerSecond
+	timeStr := fmt.Sprintf("TIME: %02d:%02d", seconds/60, seconds%60)
+
+	drawWorld(screen, r.accessor, r.world)
+	drawTopBar(screen, r.accessor, scoreStr, strings.ToUpper(r.world.Mode().Name()), timeStr)
+
+	controls := r.accessor.Input()
+	back := keyHint(controls, config.ActionBack)
+	pause := keyHint(controls, config.ActionPause)
+	var hint string
+	switch {
+	case r.world.IsOver():
+		hint = "REPLAY FINISHED. " + keyHint(controls, config.ActionConfirm) + " - again, " + back + " - back"
+	case r.isPaused:
+		hint = "PAUSED. " + pause + " - resume, " + back + " - back"
+	default:
+		hint = pause + " - pause, hold " + keyHint(controls, config.ActionP1Right) + " - fast forward, " + back + " - back"
+	}
+	hintBounds := text.BoundString(assets.UIFont, hint)
+	text.Draw(screen, hint, assets.UIFont, (layout.Width-hintBounds.Dx())/2, layout.Height-20, color.White)
+}
+
+func (r *ReplayScene) OnEnter() {
+	r.accessor.Logger().Info("Entering replay scene", "level", r.replay.Level.Name, "seed", r.replay.Seed)
+	r.nextState = core.ReplayState
+}
@@ -3,10 +3,17 @@ package scenes
 import (
 	"github.com/hajimehoshi/ebiten/v2"
 	"log/slog"
+	"snake-game/internal/ai"
 	"snake-game/internal/assets"
+	"snake-game/internal/audio"
 	"snake-game/internal/config"
 	"snake-game/internal/core"
+	"snake-game/internal/input"
+	"snake-game/internal/replay"
+	"snake-game/internal/sim"
 	"snake-game/internal/storage"
+	"snake-game/internal/ui"
+	"strings"
 	"time"
 )
 
@@ -16,17 +23,74 @@ type Scene interface {
 	OnEnter()
 }
 
+// Resizable — сцена, которая раскладывает кнопки по размеру экрана. Игра вызывает Relayout,
+// когда окно меняет размер.
+type Resizable interface {
+	Relayout()
+}
+
 type GameAccessor interface {
 	// Методы для доступа к общим ресурсам
 	Config() *config.Config
 	Assets() *assets.Assets
+	Skins() *assets.Registry
+	Audio() audio.Player
+	Input() *input.Map
 	Logger() *slog.Logger
+	// Screen — логический экран, к центру и краям которого привязан интерфейс
+	Screen() ui.Screen
 	Repository() storage.Repository
-	Score() int
+	Score(player int) int
 	GameTime() time.Duration
+	LastReplay() *replay.Replay
 
 	// Методы для управления состоянием
-	NotifyFoodEaten() (shouldIncreaseSpeed bool)
+	NotifyScored(player int)
 	Reset() error
-	StartGame(level *core.Level)
+	StartGame(setup GameSetup)
+	FinishGame(rep *replay.Replay)
+	WatchReplay(rep *replay.Replay, returnState core.GameState)
+	// OpenSettings открывает экран настроек, который по выходу вернётся в returnState
+	OpenSettings(returnState core.GameState)
+}
+
+// GameSetup — параметры, с которыми запускается игра.
+type GameSetup struct {
+	Level   *core.Level
+	Mode    sim.GameMode
+	Players int
+	// Bots[i] управляет игроком i вместо человека; nil или пустой срез — все игроки люди
+	Bots []ai.Controller
+	// Seed — зерно мира; 0 — каждая игра и перезапуск со случайным зерном
+	Seed uint64
+
+	// TargetScore — счёт, нужный для прохождения уровня кампании; 0 — цели нет
+	TargetScore int
+	// PlayerName подставляется в поле имени первого игрока на экране конца игры
+	PlayerName string
+	// ReturnState — куда ведёт кнопка выхода на экране конца игры; по умолчанию главное меню
+	ReturnState core.GameState
+	// OnFinish вызывается с повтором, когда игра заканчивается
+	OnFinish func(rep *replay.Replay)
+	// PlayTest — пробная игра из редактора: после смерти или по Esc игра сразу
+	// возвращается в ReturnState, без экрана конца игры
+	PlayTest bool
+}
+
+// keyHint перечисляет клавиши действий для подсказки на экране, например "W/S/A/D":
+// подсказки следуют за назначениями из настроек.
+func keyHint(controls *input.Map, actions ...string) string {
+	names := make([]string, len(actions))
+	for i, action := range actions {
+		names[i] = strings.ToUpper(controls.KeyName(action))
+	}
+	return strings.Join(names, "/")
+}
+
+// bot возвращает контроллер игрока или nil, если играет человек.
+func (s GameSetup) bot(player int) ai.Controller {
+	if player < len(s.Bots) {
+		return s.Bots[player]
+	}
+	return nil
 }
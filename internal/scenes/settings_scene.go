@@ -0,0 +1,617 @@
+package scenes
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"image"
+	"image/color"
+	"math"
+	"slices"
+	"snake-game/internal/assets"
+	"snake-game/internal/config"
+	"snake-game/internal/core"
+	"snake-game/internal/ui"
+	"strings"
+)
+
+const (
+	settingsTopY       = 160
+	settingsRowHeight  = 45
+	settingsLabelWidth = 300
+	settingsValueWidth = 260
+	// settingsLeftRows — сколько строк в левой колонке; клавиши идут в правую
+	settingsLeftRows = 11
+	// settingsArrowWidth — ширина стрелок "<" и ">" по краям значения, по ним можно кликать
+	settingsArrowWidth = 40
+	// settingsPadWidth — ширина колонки с кнопками геймпада справа от клавиш
+	settingsPadWidth = 220
+	// tileSizeStep — шаг размера клетки фоновой партии меню
+	tileSizeStep = 8
+)
+
+var savedColor = color.RGBA{R: 120, G: 220, B: 120, A: 255}
+
+// settingRow — строка экрана настроек: обычная настройка или клавиша действия.
+type settingRow struct {
+	label string
+	// key — имя настройки в файле конфигурации; пусто у клавиш
+	key string
+	// action — действие, которому назначаются клавиша и кнопка геймпада; пусто у обычных настроек
+	action string
+	value  func(s *config.Settings) string
+	change func(s *config.Settings, delta int)
+}
+
+func (r settingRow) isKey() bool {
+	return r.action != ""
+}
+
+// SettingsScene — правка настроек игры. Изменения собираются в черновике и попадают
+// в файл конфигурации только по SAVE.
+type SettingsScene struct {
+	accessor    GameAccessor
+	returnState core.GameState
+
+	rows     []settingRow
+	draft    config.Settings
+	selected int
+	// listening — ждём нажатия клавиши или кнопки геймпада для выбранного действия
+	listening bool
+	// previews — загруженные для превью спрайты скинов по ID
+	previews map[string]*assets.Sprites
+
+	status      string
+	statusColor color.Color
+
+	nextState      core.GameState
+	saveButton     *ui.Button
+	defaultsButton *ui.Button
+	backButton     *ui.Button
+}
+
+func NewSettingsScene(accessor GameAccessor, returnState core.GameState) *SettingsScene {
+	scene := &SettingsScene{
+		accessor:    accessor,
+		returnState: returnState,
+		nextState:   core.SettingsState,
+		previews:    make(map[string]*assets.Sprites),
+	}
+	scene.rows = scene.settingRows()
+
+	scene.Relayout()
+	return scene
+}
+
+// Relayout ставит кнопки по центру у нижнего края экрана; строки настроек считаются
+// от центра экрана при каждой отрисовке.
+func (s *SettingsScene) Relayout() {
+	layout := s.accessor.Screen()
+	centerX := float64(layout.Width) / 2
+	buttonY := float64(layout.Height - 110)
+	s.saveButton = ui.NewButton(centerX-380, buttonY, 240, 50, "SAVE", s.save)
+	s.defaultsButton = ui.NewButton(centerX-120, buttonY, 240, 50, "DEFAULTS", s.resetToDefaults)
+	s.backButton = ui.NewButton(centerX+140, buttonY, 240, 50, "BACK", s.back)
+}
+
+func (s *SettingsScene) settingRows() []settingRow {
+	skins := make([]string, 0)
+	for _, skin := range s.accessor.Skins().Skins() {
+		skins = append(skins, skin.ID)
+	}
+
+	rows := []settingRow{
+		{
+			label: "Window width", key: "screen_width",
+			value: func(st *config.Settings) string { return fmt.Sprintf("%d", st.ScreenWidth) },
+			change: func(st *config.Settings, delta int) {
+				st.ScreenWidth = max(st.ScreenWidth+delta*st.TileSize, st.TileSize)
+			},
+		},
+		{
+			label: "Field height", key: "screen_height",
+			value: func(st *config.Settings) string { return fmt.Sprintf("%d", st.ScreenHeight) },
+			change: func(st *config.Settings, delta int) {
+				st.ScreenHeight = max(st.ScreenHeight+delta*st.TileSize, st.TileSize)
+			},
+		},
+		{
+			label: "Tile size", key: "tile_size",
+			value:  func(st *config.Settings) string { return fmt.Sprintf("%d", st.TileSize) },
+			change: changeTileSize,
+		},
+		{
+			label: "Initial length", key: "initial_snake_len",
+			value: func(st *config.Settings) string { return fmt.Sprintf("%d", st.InitialSnakeLen) },
+			change: func(st *config.Settings, delta int) {
+				st.InitialSnakeLen = min(max(st.InitialSnakeLen+delta, core.MinSnakeLength), config.MaxSnakeLength)
+			},
+		},
+		{
+			label: "Initial speed", key: "initial_speed",
+			value:  func(st *config.Settings) string { return fmt.Sprintf("%d ticks", st.InitialSpeed) },
+			change: func(st *config.Settings, delta int) { st.InitialSpeed = max(st.InitialSpeed+delta, 1) },
+		},
+		{
+			label: "Speed-up every", key: "speed_increase_interval",
+			value: func(st *config.Settings) string { return fmt.Sprintf("%d points", st.SpeedIncreaseInterval) },
+			change: func(st *config.Settings, delta int) {
+				st.SpeedIncreaseInterval = max(st.SpeedIncreaseInterval+delta, 1)
+			},
+		},
+		{
+			label: "Speed-up by", key: "speed_increase_amount",
+			value:  func(st *config.Settings) string { return fmt.Sprintf("%d ticks", st.SpeedIncreaseAmount) },
+			change: func(st *config.Settings, delta int) { st.SpeedIncreaseAmount = max(st.SpeedIncreaseAmount+delta, 0) },
+		},
+		{
+			label: "Max speed", key: "max_speed",
+			value:  func(st *config.Settings) string { return fmt.Sprintf("%d ticks", st.MaxSpeed) },
+			change: func(st *config.Settings, delta int) { st.MaxSpeed = max(st.MaxSpeed+delta, 1) },
+		},
+		{
+			label: "Skin", key: "skin",
+			value: func(st *config.Settings) string {
+				if skin, ok := s.accessor.Skins().Skin(st.Skin); ok {
+					return skin.Title()
+				}
+				return st.Skin
+			},
+			change: func(st *config.Settings, delta int) {
+				if len(skins) == 0 {
+					return
+				}
+				// неизвестный скин из файла даёт индекс -1: шаг вперёд выбирает первый
+				current := slices.Index(skins, st.Skin)
+				if current < 0 && delta < 0 {
+					current = 0
+				}
+				st.Skin = skins[(current+delta+len(skins))%len(skins)]
+			},
+		},
+		{
+			label: "Volume", key: "volume",
+			value: func(st *config.Settings) string { return fmt.Sprintf("%d%%", int(math.Round(st.Volume*100))) },
+			change: func(st *config.Settings, delta int) {
+				st.Volume = math.Round(min(max(st.Volume+float64(delta)*0.1, 0), 1)*10) / 10
+			},
+		},
+		{
+			label: "Sound", key: "muted",
+			value: func(st *config.Settings) string {
+				if st.Muted {
+					return "OFF"
+				}
+				return "ON"
+			},
+			change: func(st *config.Settings, delta int) { st.Muted = !st.Muted },
+		},
+		{
+			label: "Keys preset",
+			value: func(st *config.Settings) string {
+				if preset := st.KeyPreset(); preset != "" {
+					return strings.ToUpper(preset)
+				}
+				return "CUSTOM"
+			},
+			change: func(st *config.Settings, delta int) {
+				presets := config.KeyPresets()
+				current := slices.Index(presets, st.KeyPreset())
+				if current < 0 && delta < 0 {
+					current = 0
+				}
+				// раскладка из списка всегда известна
+				_ = st.ApplyKeyPreset(presets[(current+delta+len(presets))%len(presets)])
+			},
+		},
+	}
+
+	for _, action := range config.Actions() {
+		rows = append(rows, settingRow{
+			label:  strings.ToUpper(strings.ReplaceAll(action, "_", " ")),
+			action: action,
+			value:  func(st *config.Settings) string { return strings.ToUpper(st.KeyBindings[action]) },
+		})
+	}
+	return rows
+}
+
+// changeTileSize меняет размер клетки шагом tileSizeStep в допустимых границах.
+func changeTileSize(st *config.Settings, delta int) {
+	st.TileSize = min(max(st.TileSize+delta*tileSizeStep, config.MinTileSize), config.MaxTileSize)
+}
+
+func (s *SettingsScene) OnEnter() {
+	s.accessor.Logger().Info("entering settings scene", "config", s.accessor.Config().Path)
+	s.nextState = core.SettingsState
+	s.draft = s.accessor.Config().Saved()
+	s.selected = 0
+	s.listening = false
+	s.status = ""
+}
+
+func (s *SettingsScene) Update() (core.GameState, error) {
+	if s.listening {
+		s.captureKey()
+		return s.nextState, nil
+	}
+
+	s.saveButton.Update()
+	s.defaultsButton.Update()
+	s.backButton.Update()
+	s.handleMouse()
+	s.handleKeyboard()
+	return s.nextState, nil
+}
+
+func (s *SettingsScene) handleKeyboard() {
+	controls := s.accessor.Input()
+	if controls.JustPressed(config.ActionBack) {
+		s.back()
+		return
+	}
+	if controls.JustPressed(config.ActionConfirm) {
+		if s.rows[s.selected].isKey() {
+			s.startListening()
+		}
+		return
+	}
+	if s.rows[s.selected].isKey() && inpututil.IsKeyJustPressed(ebiten.KeyBackspace) {
+		action := s.rows[s.selected].action
+		s.draft.GamepadBindings[action] = ""
+		s.accessor.Logger().Info("gamepad button cleared", "action", action)
+		return
+	}
+
+	direction, ok := controls.MenuDirection()
+	if !ok {
+		return
+	}
+	switch direction {
+	case core.Up:
+		s.selected = (s.selected + len(s.rows) - 1) % len(s.rows)
+	case core.Down:
+		s.selected = (s.selected + 1) % len(s.rows)
+	case core.Left:
+		s.change(s.selected, -1)
+	case core.Right:
+		s.change(s.selected, 1)
+	}
+}
+
+func (s *SettingsScene) handleMouse() {
+	if !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return
+	}
+	cursor := image.Pt(ebiten.CursorPosition())
+	for i, row := range s.rows {
+		valueRect := s.valueRect(i)
+		if !cursor.In(s.rowRect(i)) && !(row.isKey() && cursor.In(s.padRect(i))) {
+			continue
+		}
+		s.selected = i
+		switch {
+		case row.isKey():
+			s.startListening()
+		case cursor.X < valueRect.Min.X+settingsArrowWidth && cursor.X >= valueRect.Min.X:
+			s.change(i, -1)
+		case cursor.X >= valueRect.Max.X-settingsArrowWidth && cursor.X < valueRect.Max.X:
+			s.change(i, 1)
+		}
+		return
+	}
+}
+
+// change меняет значение строки на delta шагов; переопределённые настройки заблокированы.
+func (s *SettingsScene) change(index, delta int) {
+	row := s.rows[index]
+	if row.change == nil {
+		return
+	}
+	if source := s.accessor.Config().Overridden(row.key); source != "" {
+		s.setStatus("SET BY "+strings.ToUpper(source)+", CAN'T CHANGE HERE", problemColor)
+		return
+	}
+	row.change(&s.draft, delta)
+	s.status = ""
+}
+
+func (s *SettingsScene) startListening() {
+	s.listening = true
+	s.setStatus("PRESS A KEY OR GAMEPAD BUTTON FOR "+s.rows[s.selected].label+", ESC - CANCEL", color.White)
+}
+
+// captureKey назначает выбранному действию первую нажатую клавишу или кнопку геймпада.
+// Занятая другим действием клавиша не запрещается: конфликт покажет проверка настроек.
+func (s *SettingsScene) captureKey() {
+	action := s.rows[s.selected].action
+	if button, ok := s.accessor.Input().JustPressedButton(); ok {
+		s.listening = false
+		s.draft.GamepadBindings[action] = button
+		s.accessor.Logger().Info("gamepad button rebound", "action", action, "button", button)
+		s.status = ""
+		return
+	}
+
+	keys := inpututil.AppendJustPressedKeys(nil)
+	if len(keys) == 0 {
+		return
+	}
+	s.listening = false
+
+	key := keys[0]
+	if key == ebiten.KeyEscape {
+		s.status = ""
+		return
+	}
+
+	s.draft.KeyBindings[action] = key.String()
+	s.accessor.Logger().Info("key rebound", "action", action, "key", key.String())
+	s.status = ""
+}
+
+func (s *SettingsScene) save() {
+	cfg := s.accessor.Config()
+	skin, ok := s.accessor.Skins().Skin(s.draft.Skin)
+	if !ok || !skin.IsValid() {
+		s.setStatus("CAN'T SAVE: SKIN "+strings.ToUpper(s.draft.Skin)+" IS NOT AVAILABLE", problemColor)
+		return
+	}
+
+	restart, err := cfg.SaveSettings(s.draft)
+	if err != nil {
+		s.accessor.Logger().Warn("failed to save settings", "path", cfg.Path, "error", err)
+		s.setStatus("CAN'T SAVE: "+err.Error(), problemColor)
+		return
+	}
+	s.accessor.Logger().Info("settings saved", "path", cfg.Path, "restart", restart)
+
+	s.accessor.Input().SetBindings(cfg.Settings)
+	s.accessor.Audio().SetVolume(cfg.Volume)
+	s.accessor.Audio().SetMuted(cfg.Muted)
+
+	// скин меняется сразу: все сцены берут спрайты из общих ассетов
+	if assets := s.accessor.Assets(); assets.Skin.ID != cfg.Skin {
+		if err := assets.UseSkin(skin); err != nil {
+			s.accessor.Logger().Error("failed to switch skin", "skin", skin.ID, "error", err)
+			s.setStatus("SAVED, BUT CAN'T SWITCH SKIN: "+err.Error(), problemColor)
+			return
+		}
+		s.accessor.Logger().Info("skin switched", "skin", skin.ID)
+	}
+
+	if restart {
+		s.setStatus("SAVED. RESTART THE GAME TO APPLY SETTINGS MARKED WITH *", savedColor)
+	} else {
+		s.setStatus("SAVED", savedColor)
+	}
+}
+
+func (s *SettingsScene) resetToDefaults() {
+	s.draft = s.accessor.Config().Defaults()
+	s.setStatus("DEFAULTS RESTORED, PRESS SAVE TO KEEP THEM", color.White)
+}
+
+func (s *SettingsScene) back() {
+	s.accessor.Logger().Info("leaving settings scene")
+	s.nextState = s.returnState
+}
+
+func (s *SettingsScene) setStatus(status string, clr color.Color) {
+	s.status = status
+	s.statusColor = clr
+}
+
+// rowRect — область строки index: колонки слева и справа от центра экрана.
+func (s *SettingsScene) rowRect(index int) image.Rectangle {
+	centerX := s.accessor.Screen().Width / 2
+	x := centerX - settingsLabelWidth - settingsValueWidth - 40
+	row := index
+	if index >= settingsLeftRows {
+		x = centerX + 40
+		row = index - settingsLeftRows
+	}
+	y := settingsTopY + row*settingsRowHeight
+	return image.Rect(x, y, x+settingsLabelWidth+settingsValueWidth, y+settingsRowHeight-5)
+}
+
+// padRect — ячейка кнопки геймпада в строке действия.
+func (s *SettingsScene) padRect(index int) image.Rectangle {
+	rect := s.rowRect(index)
+	return image.Rect(rect.Max.X+10, rect.Min.Y, rect.Max.X+10+settingsPadWidth, rect.Max.Y)
+}
+
+func (s *SettingsScene) valueRect(index int) image.Rectangle {
+	rect := s.rowRect(index)
+	return image.Rect(rect.Min.X+settingsLabelWidth, rect.Min.Y, rect.Max.X, rect.Max.Y)
+}
+
+func (s *SettingsScene) Draw(screen *ebiten.Image) {
+	cfg := s.accessor.Config()
+	layout := s.accessor.Screen()
+	assets := s.accessor.Assets()
+	uiFont := assets.UIFont
+
+	screen.Fill(color.RGBA{R: 20, G: 20, B: 40, A: 255})
+
+	title := "SETTINGS"
+	titleBounds := text.BoundString(assets.TitleFont, title)
+	text.Draw(screen, title, assets.TitleFont, (layout.Width-titleBounds.Dx())/2, 60, color.White)
+
+	path := "Config: " + cfg.Path
+	pathBounds := text.BoundString(uiFont, path)
+	text.Draw(screen, path, uiFont, (layout.Width-pathBounds.Dx())/2, 110, color.Gray{Y: 180})
+
+	for i := range s.rows {
+		s.drawRow(screen, i)
+	}
+	if firstKey := slices.IndexFunc(s.rows, settingRow.isKey); firstKey >= 0 {
+		header := fmt.Sprintf("GAMEPAD (%d)", s.accessor.Input().Gamepads())
+		headerRect := s.padRect(firstKey)
+		text.Draw(screen, header, uiFont, headerRect.Min.X, headerRect.Min.Y-12, color.Gray{Y: 180})
+	}
+
+	previewBottom := s.drawSkinPreview(screen)
+
+	bottom := s.rowRect(settingsLeftRows - 1).Max.Y
+	note := "* applies after restart"
+	text.Draw(screen, note, uiFont, s.rowRect(0).Min.X, bottom+35, color.Gray{Y: 180})
+
+	// пока черновик неисправен, показываем первые проблемы, чтобы было понятно, что поправить
+	problemsY := max(bottom+80, previewBottom+40)
+	if err := s.draft.Validate(); err != nil {
+		problems := strings.Split(strings.TrimPrefix(err.Error(), "invalid settings: "), "; ")
+		for i, problem := range problems[:min(len(problems), 3)] {
+			problemBounds := text.BoundString(uiFont, problem)
+			text.Draw(screen, problem, uiFont, (layout.Width-problemBounds.Dx())/2, problemsY+i*30, problemColor)
+		}
+	}
+
+	if s.status != "" {
+		statusBounds := text.BoundString(uiFont, s.status)
+		text.Draw(screen, s.status, uiFont, (layout.Width-statusBounds.Dx())/2, layout.Height-130, s.statusColor)
+	}
+
+	s.saveButton.Draw(screen, assets)
+	s.defaultsButton.Draw(screen, assets)
+	s.backButton.Draw(screen, assets)
+
+	hint := "UP/DOWN - select, LEFT/RIGHT - change, ENTER - rebind key or button, BACKSPACE - clear button, ESC - back"
+	hintBounds := text.BoundString(uiFont, hint)
+	text.Draw(screen, hint, uiFont, (layout.Width-hintBounds.Dx())/2, layout.Height-20, color.Gray{Y: 180})
+}
+
+func (s *SettingsScene) drawRow(screen *ebiten.Image, index int) {
+	cfg := s.accessor.Config()
+	assets := s.accessor.Assets()
+	uiFont := assets.UIFont
+	row := s.rows[index]
+	rect := s.rowRect(index)
+	valueRect := s.valueRect(index)
+
+	if index == s.selected {
+		width := rect.Dx() + 20
+		if row.isKey() {
+			width += settingsPadWidth + 10
+		}
+		ui.DrawRectangle(screen, assets, float64(rect.Min.X-10), float64(rect.Min.Y), float64(width), float64(rect.Dy()), color.RGBA{R: 60, G: 60, B: 100, A: 255})
+	}
+	if row.isKey() {
+		s.drawPadBinding(screen, index)
+	}
+
+	label := row.label
+	if config.RequiresRestart(row.key) {
+		label += " *"
+	}
+	text.Draw(screen, label, uiFont, rect.Min.X, rect.Min.Y+28, color.White)
+
+	ui.DrawRectangle(screen, assets, float64(valueRect.Min.X), float64(valueRect.Min.Y+2), float64(valueRect.Dx()), float64(valueRect.Dy()-4), color.Black)
+
+	value := row.value(&s.draft)
+	var valueColor color.Color = color.White
+	switch {
+	case row.isKey() && s.listening && index == s.selected:
+		value, valueColor = "...", color.RGBA{R: 255, G: 200, B: 0, A: 255}
+	case row.isKey():
+	case cfg.Overridden(row.key) != "":
+		// значение задано флагом или переменной окружения и здесь не меняется
+		value = value + " [" + strings.ToUpper(strings.Fields(cfg.Overridden(row.key))[0]) + "]"
+		valueColor = lockedColor
+	default:
+		text.Draw(screen, "<", uiFont, valueRect.Min.X+12, rect.Min.Y+28, color.White)
+		text.Draw(screen, ">", uiFont, valueRect.Max.X-28, rect.Min.Y+28, color.White)
+	}
+	valueBounds := text.BoundString(uiFont, value)
+	valueX := valueRect.Min.X + (valueRect.Dx()-valueBounds.Dx())/2
+	text.Draw(screen, value, uiFont, valueX, rect.Min.Y+28, valueColor)
+}
+
+// drawPadBinding рисует справа от клавиши действия кнопку геймпада, назначенную тому же действию.
+func (s *SettingsScene) drawPadBinding(screen *ebiten.Image, index int) {
+	assets := s.accessor.Assets()
+	uiFont := assets.UIFont
+	rect := s.padRect(index)
+	ui.DrawRectangle(screen, assets, float64(rect.Min.X), float64(rect.Min.Y+2), float64(rect.Dx()), float64(rect.Dy()-4), color.Black)
+
+	button := strings.ToUpper(s.draft.GamepadBindings[s.rows[index].action])
+	var buttonColor color.Color = color.White
+	if button == "" {
+		button, buttonColor = "-", color.Gray{Y: 120}
+	}
+	bounds := text.BoundString(uiFont, button)
+	text.Draw(screen, button, uiFont, rect.Min.X+(rect.Dx()-bounds.Dx())/2, rect.Min.Y+28, buttonColor)
+}
+
+// preview возвращает спрайты скина для превью, загружая их при первом обращении.
+func (s *SettingsScene) preview(skin *assets.Skin) (*assets.Sprites, error) {
+	if sprites, ok := s.previews[skin.ID]; ok {
+		return sprites, nil
+	}
+	sprites, err := assets.LoadSprites(skin)
+	if err != nil {
+		return nil, err
+	}
+	s.previews[skin.ID] = sprites
+	return sprites, nil
+}
+
+// drawSkinPreview рисует под настройками кусочек поля в выбранном скине: змею с поворотом, еду и стены.
+// Возвращает нижнюю границу превью.
+func (s *SettingsScene) drawSkinPreview(screen *ebiten.Image) int {
+	const (
+		tileSize    = 64
+		gridWidth   = 7
+		gridHeight  = 2
+		titleHeight = 40
+	)
+	uiAssets := s.accessor.Assets()
+	uiFont := uiAssets.UIFont
+	// превью идёт в левой колонке под заметкой о перезапуске: правую занимают клавиши
+	x := s.rowRect(0).Min.X
+	y := s.rowRect(settingsLeftRows-1).Max.Y + 60
+
+	skin, ok := s.accessor.Skins().Skin(s.draft.Skin)
+	if !ok {
+		text.Draw(screen, "SKIN "+strings.ToUpper(s.draft.Skin)+" NOT FOUND", uiFont, x, y+28, problemColor)
+		return y + 28
+	}
+	title := "Preview: " + skin.Title()
+	if skin.Author != "" {
+		title += " by " + skin.Author
+	}
+	text.Draw(screen, title, uiFont, x, y+28, color.White)
+
+	if !skin.IsValid() {
+		text.Draw(screen, "INVALID SKIN: "+skin.Err.Error(), uiFont, x, y+titleHeight+28, problemColor)
+		return y + titleHeight + 28
+	}
+	sprites, err := s.preview(skin)
+	if err != nil {
+		text.Draw(screen, "CAN'T LOAD SKIN: "+err.Error(), uiFont, x, y+titleHeight+28, problemColor)
+		return y + titleHeight + 28
+	}
+
+	fieldY := float64(y + titleHeight)
+	ui.DrawRectangle(screen, uiAssets, float64(x), fieldY, gridWidth*tileSize, gridHeight*tileSize, color.NRGBA{R: 0x10, G: 0x10, B: 0x10, A: 0xff})
+
+	snake := &core.Snake{
+		Direction: core.Right,
+		Body: []core.SnakeSegment{
+			*core.NewSnakeSegment(4, 0), *core.NewSnakeSegment(3, 0), *core.NewSnakeSegment(2, 0),
+			*core.NewSnakeSegment(2, 1), *core.NewSnakeSegment(1, 1), *core.NewSnakeSegment(0, 1),
+		},
+	}
+	drawSnakeSprites(screen, sprites, snake, 1, float64(x), fieldY, tileSize, color.White)
+	drawTile(screen, sprites.Apple, float64(x+6*tileSize), fieldY, tileSize, 0, color.White)
+	drawTile(screen, sprites.Wall, float64(x+5*tileSize), fieldY+tileSize, tileSize, 0, color.White)
+	drawTile(screen, sprites.Wall, float64(x+6*tileSize), fieldY+tileSize, tileSize, 0, color.White)
+
+	bottom := int(fieldY) + gridHeight*tileSize
+	if skin.Description != "" {
+		bottom += 30
+		text.Draw(screen, skin.Description, uiFont, x, bottom, color.Gray{Y: 180})
+	}
+	return bottom
+}
@@ -0,0 +1,236 @@
+package scenes
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"image/color"
+	"snake-game/internal/assets"
+	"snake-game/internal/core"
+	"snake-game/internal/sim"
+	"snake-game/internal/ui"
+	"strings"
+)
+
+// drawWorld рисует поле, змею, еду и стены; используется игрой и просмотром повторов.
+// Поле вписывается в экран под верхней панелью, размер клетки зависит от размеров уровня и окна.
+func drawWorld(screen *ebiten.Image, accessor GameAccessor, world *sim.World) {
+	assets := accessor.Assets()
+	field := accessor.Screen().Field(world.Level.GridWidth, world.Level.GridHeight)
+
+	ui.DrawRectangle(
+		screen,
+		assets,
+		field.X,
+		field.Y,
+		field.Width(),
+		field.Height(),
+		color.NRGBA{R: 0x10, G: 0x10, B: 0x10, A: 0xff},
+	)
+
+	for player, snake := range world.Snakes {
+		if snake.IsAlive {
+			// после конца игры змеи стоят ровно в клетках, где их застала игра
+			progress := snake.MoveProgress()
+			if world.IsOver() {
+				progress = 1
+			}
+			drawSnakeSprites(screen, &assets.Sprites, snake, progress, field.X, field.Y, field.TileSize, playerTint(player))
+		}
+	}
+
+	drawFood(screen, assets, field, world.Food)
+	drawWalls(screen, assets, field, world.Level.Walls)
+}
+
+// playerTint — цвет, которым подкрашивается змея игрока, чтобы игроков можно было различить.
+func playerTint(player int) color.Color {
+	tints := []color.Color{
+		color.White,
+		color.RGBA{R: 0x80, G: 0xc0, B: 0xff, A: 0xff},
+		color.RGBA{R: 0xff, G: 0xc0, B: 0x60, A: 0xff},
+		color.RGBA{R: 0xc0, G: 0xff, B: 0x80, A: 0xff},
+	}
+	return tints[player%len(tints)]
+}
+
+// scoresText — счёт для верхней панели: общий для одного игрока, по игрокам для нескольких.
+func scoresText(players int, score func(player int) int) string {
+	if players <= 1 {
+		return fmt.Sprintf("SCORE: %d", score(0))
+	}
+	parts := make([]string, players)
+	for player := range parts {
+		parts[player] = fmt.Sprintf("P%d: %d", player+1, score(player))
+	}
+	return strings.Join(parts, "  ")
+}
+
+func drawTopBar(screen *ebiten.Image, accessor GameAccessor, left, center, right string) {
+	layout := accessor.Screen()
+	assets := accessor.Assets()
+
+	opBar := &ebiten.DrawImageOptions{}
+	opBar.GeoM.Scale(float64(layout.Width), float64(layout.TopBarHeight))
+	screen.DrawImage(assets.WhitePixel, opBar)
+
+	text.Draw(screen, left, assets.UIFont, 10, 25, color.Black)
+	centerBounds := text.BoundString(assets.UIFont, center)
+	text.Draw(screen, center, assets.UIFont, (layout.Width-centerBounds.Dx())/2, 25, color.Black)
+	text.Draw(screen, right, assets.UIFont, layout.Width-200, 25, color.Black)
+}
+
+// drawSnakeSprites рисует змею спрайтами sprites на поле с левым верхним углом (originX, originY)
+// и клетками размера tileSize; так же рисуется и превью скина в настройках.
+// Змея ходит по клеткам, но рисуется с отставанием на шаг: progress — доля пути от прошлого
+// шага к нынешнему, голова выезжает из прошлой клетки, хвост доползает до новой, а тело
+// под ними уже лежит в клетках. При progress 1 змея стоит ровно в клетках.
+func drawSnakeSprites(screen *ebiten.Image, sprites *assets.Sprites, snake *core.Snake, progress, originX, originY, tileSize float64, tint color.Color) {
+	tile := func(img *ebiten.Image, x, y, rotation float64) {
+		drawTile(screen, img, originX+x*tileSize, originY+y*tileSize, tileSize, rotation, tint)
+	}
+
+	// рисуем от хвоста к голове, чтобы голова оказалась поверх тела
+	last := len(snake.Body) - 1
+	for i := last; i >= 0; i-- {
+		segment := snake.Body[i].Position
+		switch {
+		case i == last && drawMovingTail(sprites, snake, progress, tile):
+		case i == 0 && drawMovingHead(sprites, snake, progress, tile):
+		default:
+			img, rotation := snakeSprite(sprites, snake, i)
+			tile(img, float64(segment.X), float64(segment.Y), rotation)
+		}
+	}
+}
+
+// drawMovingHead рисует голову на пути из второй клетки змеи в первую, доворачивая её
+// от прошлого направления к нынешнему. Возвращает false, если вести голову не откуда:
+// змея ещё не шагала или прошла сквозь край поля.
+func drawMovingHead(sprites *assets.Sprites, snake *core.Snake, progress float64, tile func(img *ebiten.Image, x, y, rotation float64)) bool {
+	if _, stepped := snake.PreviousTail(); !stepped || progress >= 1 {
+		return false
+	}
+	from, to := snake.Body[1].Position, snake.Body[0].Position
+	x, y, ok := lerpTile(from, to, progress)
+	if !ok {
+		return false
+	}
+
+	previousDirection := snake.Direction
+	if len(snake.Body) > 2 && isAdjacent(snake.Body[2].Position, from) {
+		previousDirection = core.GetDirection(from, snake.Body[2].Position)
+	}
+	tile(sprites.SnakeHead, x, y, core.InterpolateRotationAngle(previousDirection, snake.Direction, progress))
+	return true
+}
+
+// drawMovingTail рисует хвост на пути из клетки, которую он освободил, в нынешнюю клетку.
+// Под ним в нынешней клетке лежит кусок тела — прямой или угловой, — иначе между хвостом
+// и телом была бы дыра. Возвращает false, если хвост на последнем шаге не двигался.
+func drawMovingTail(sprites *assets.Sprites, snake *core.Snake, progress float64, tile func(img *ebiten.Image, x, y, rotation float64)) bool {
+	previousTail, stepped := snake.PreviousTail()
+	last := len(snake.Body) - 1
+	tail := snake.Body[last].Position
+	if !stepped || progress >= 1 || previousTail == tail {
+		return false
+	}
+	x, y, ok := lerpTile(previousTail, tail, progress)
+	if !ok || !isAdjacent(snake.Body[last-1].Position, tail) {
+		return false
+	}
+
+	oldDirection := core.GetDirection(tail, previousTail)
+	newDirection := core.GetDirection(snake.Body[last-1].Position, tail)
+	if newDirection == oldDirection {
+		tile(sprites.SnakeBody, float64(tail.X), float64(tail.Y), core.DirectionToRotationAngle(oldDirection))
+	} else {
+		tile(sprites.SnakeBodyCorner, float64(tail.X), float64(tail.Y), core.CornerToRotationAngle(oldDirection, newDirection))
+	}
+	tile(sprites.SnakeTail, x, y, core.InterpolateRotationAngle(oldDirection, newDirection, progress))
+	return true
+}
+
+// lerpTile — точка на пути из клетки from в соседнюю клетку to в клетках поля.
+// Для несоседних клеток (змея вышла за край и появилась с другой стороны) возвращает false.
+func lerpTile(from, to core.Position, progress float64) (x, y float64, ok bool) {
+	if !isAdjacent(from, to) {
+		return 0, 0, false
+	}
+	x = float64(from.X) + float64(to.X-from.X)*progress
+	y = float64(from.Y) + float64(to.Y-from.Y)*progress
+	return x, y, true
+}
+
+func isAdjacent(a, b core.Position) bool {
+	return abs(a.X-b.X)+abs(a.Y-b.Y) == 1
+}
+
+// snakeSprite выбирает спрайт и поворот для сегмента i: голова, хвост, прямой или угловой кусок тела.
+func snakeSprite(sprites *assets.Sprites, snake *core.Snake, i int) (*ebiten.Image, float64) {
+	segment := snake.Body[i]
+	if i == 0 {
+		return sprites.SnakeHead, core.DirectionToRotationAngle(snake.Direction)
+	}
+	if i == len(snake.Body)-1 {
+		direction := core.GetDirection(snake.Body[i-1].Position, segment.Position)
+		return sprites.SnakeTail, core.DirectionToRotationAngle(direction)
+	}
+
+	newDirection := core.GetDirection(snake.Body[i-1].Position, segment.Position)
+	oldDirection := core.GetDirection(segment.Position, snake.Body[i+1].Position)
+	if newDirection == oldDirection {
+		return sprites.SnakeBody, core.DirectionToRotationAngle(oldDirection)
+	}
+	return sprites.SnakeBodyCorner, core.CornerToRotationAngle(oldDirection, newDirection)
+}
+
+// drawTile растягивает img на клетку размера size с левым верхним углом (x, y)
+// и поворачивает его вокруг центра клетки.
+func drawTile(screen *ebiten.Image, img *ebiten.Image, x, y, size, rotation float64, tint color.Color) {
+	if img == nil {
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+
+	originalWidth := img.Bounds().Dx()
+	originalHeight := img.Bounds().Dy()
+
+	var scaleX, scaleY float64
+	if originalWidth > 0 {
+		scaleX = size / float64(originalWidth)
+	}
+	if originalHeight > 0 {
+		scaleY = size / float64(originalHeight)
+	}
+
+	op.GeoM.Scale(scaleX, scaleY)
+	op.GeoM.Translate(-size/2, -size/2)
+	op.GeoM.Rotate(rotation)
+	op.GeoM.Translate(x+size/2, y+size/2)
+	op.ColorScale.ScaleWithColor(tint)
+
+	screen.DrawImage(img, op)
+}
+
+func drawFood(screen *ebiten.Image, assets *assets.Assets, field ui.Field, food *core.Food) {
+	if food == nil {
+		return
+	}
+	x, y := field.Cell(food.X, food.Y)
+	drawTile(screen, assets.Apple, x, y, field.TileSize, 0, color.White)
+}
+
+func drawWalls(screen *ebiten.Image, assets *assets.Assets, field ui.Field, walls []core.Wall) {
+	for _, wall := range walls {
+		x, y := field.Cell(wall.X, wall.Y)
+		drawTile(screen, assets.Wall, x, y, field.TileSize, 0, color.White)
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
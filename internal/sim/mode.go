@@ -0,0 +1,111 @@
+package sim
+
+import (
+	"fmt"
+)
+
+const (
+	TicksPerSecond = 60
+
+	ClassicModeName    = "classic"
+	WrapModeName       = "wrap"
+	TimeAttackModeName = "time-attack"
+	SurvivalModeName   = "survival"
+	ZenModeName        = "zen"
+
+	DefaultTimeAttackLimit = 60 * TicksPerSecond
+	DefaultSurvivalGrowth  = 10
+)
+
+// GameMode задаёт правила, которые отличают один режим игры от другого.
+type GameMode interface {
+	Name() string
+	// WrapsBorders — змея, вышедшая за край поля, появляется с противоположной стороны.
+	WrapsBorders() bool
+	// IsLethal — приводит ли столкновение данного типа к концу игры.
+	IsLethal(cause DeathCause) bool
+	SpawnsFood() bool
+	// Grows вызывается после каждого шага змеи игрока: true — змея вырастает и получает очко.
+	Grows(w *World, player int) bool
+	// IsFinished — игра закончилась по правилам режима, а не из-за смерти.
+	IsFinished(w *World) bool
+}
+
+type ClassicMode struct{}
+
+func (ClassicMode) Name() string                    { return ClassicModeName }
+func (ClassicMode) WrapsBorders() bool              { return false }
+func (ClassicMode) IsLethal(cause DeathCause) bool  { return true }
+func (ClassicMode) SpawnsFood() bool                { return true }
+func (ClassicMode) Grows(w *World, player int) bool { return false }
+func (ClassicMode) IsFinished(w *World) bool        { return false }
+
+// WrapMode — поле замкнуто в тор, смертельны только стены и собственное тело.
+type WrapMode struct {
+	ClassicMode
+}
+
+func (WrapMode) Name() string       { return WrapModeName }
+func (WrapMode) WrapsBorders() bool { return true }
+
+// TimeAttackMode — классические правила, но игра длится ограниченное число тиков.
+type TimeAttackMode struct {
+	ClassicMode
+	Limit int
+}
+
+func (TimeAttackMode) Name() string { return TimeAttackModeName }
+
+func (m TimeAttackMode) IsFinished(w *World) bool {
+	return w.Tick >= m.Limit
+}
+
+// TicksLeft возвращает, сколько тиков осталось до конца игры.
+func (m TimeAttackMode) TicksLeft(w *World) int {
+	return max(m.Limit-w.Tick, 0)
+}
+
+// SurvivalMode — еды нет, змея сама вырастает каждые GrowEvery шагов.
+type SurvivalMode struct {
+	ClassicMode
+	GrowEvery int
+}
+
+func (SurvivalMode) Name() string     { return SurvivalModeName }
+func (SurvivalMode) SpawnsFood() bool { return false }
+
+func (m SurvivalMode) Grows(w *World, player int) bool {
+	return m.GrowEvery > 0 && w.Moves[player]%m.GrowEvery == 0
+}
+
+// ZenMode — смерти нет: змея проходит сквозь стены и себя, игрок завершает игру сам.
+type ZenMode struct {
+	ClassicMode
+}
+
+func (ZenMode) Name() string                   { return ZenModeName }
+func (ZenMode) WrapsBorders() bool             { return true }
+func (ZenMode) IsLethal(cause DeathCause) bool { return false }
+
+func Modes() []GameMode {
+	return []GameMode{
+		ClassicMode{},
+		WrapMode{},
+		TimeAttackMode{Limit: DefaultTimeAttackLimit},
+		SurvivalMode{GrowEvery: DefaultSurvivalGrowth},
+		ZenMode{},
+	}
+}
+
+// ModeByName возвращает режим по имени; пустое имя означает классический режим.
+func ModeByName(name string) (GameMode, error) {
+	if name == "" {
+		return ClassicMode{}, nil
+	}
+	for _, mode := range Modes() {
+		if mode.Name() == name {
+			return mode, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown game mode %q", name)
+}
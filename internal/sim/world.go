@@ -0,0 +1,542 @@
+package sim
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"snake-game/internal/config"
+	"snake-game/internal/core"
+)
+
+// Rules — параметры скорости и длины змеи, не зависящие от отрисовки.
+type Rules struct {
+	InitialSnakeLen       int `json:"initial_snake_len"`
+	InitialSpeed          int `json:"initial_speed"`
+	SpeedIncreaseInterval int `json:"speed_increase_interval"`
+	SpeedIncreaseAmount   int `json:"speed_increase_amount"`
+	MaxSpeed              int `json:"max_speed"`
+	// TurnQueue — размер очереди поворотов; 0 — очереди нет, новый поворот заменяет
+	// предыдущий. Повторы, записанные до очереди, воспроизводятся с 0
+	TurnQueue int `json:"turn_queue,omitempty"`
+}
+
+// TurnQueueSize — сколько поворотов змея запоминает вперёд в новых играх. Хватает, чтобы
+// на максимальной скорости успеть развернуться двумя нажатиями за один шаг.
+const TurnQueueSize = 3
+
+func RulesFromConfig(cfg *config.Config) Rules {
+	return Rules{
+		InitialSnakeLen:       cfg.InitialSnakeLen,
+		InitialSpeed:          cfg.InitialSpeed,
+		SpeedIncreaseInterval: cfg.SpeedIncreaseInterval,
+		SpeedIncreaseAmount:   cfg.SpeedIncreaseAmount,
+		MaxSpeed:              cfg.MaxSpeed,
+		TurnQueue:             TurnQueueSize,
+	}
+}
+
+type DeathCause int
+
+const (
+	NotDead DeathCause = iota
+	WallCollision
+	BorderCollision
+	SelfCollision
+	SnakeCollision
+	// TimeUp и Stopped — игра окончена без смерти змеи
+	TimeUp
+	Stopped
+)
+
+func (c DeathCause) String() string {
+	switch c {
+	case WallCollision:
+		return "wall"
+	case BorderCollision:
+		return "border"
+	case SelfCollision:
+		return "self"
+	case SnakeCollision:
+		return "snake"
+	case TimeUp:
+		return "time"
+	case Stopped:
+		return "stopped"
+	default:
+		return "none"
+	}
+}
+
+// Input — направления, выбранные игроком за один тик, в порядке нажатия.
+// Finish завершает игру по желанию игрока (нужно режимам без смерти).
+type Input struct {
+	Turns  []core.Direction
+	Finish bool
+	// Replace сначала забывает ещё не выполненные повороты змеи: так ходят боты, которые
+	// принимают решение заново каждый тик, и передуманный поворот не должен исполниться
+	Replace bool
+}
+
+// Events описывает, что произошло за один вызов World.Step.
+type Events struct {
+	Moved   bool
+	AteFood bool
+	SpeedUp bool
+	Died    bool
+	// Scored — индексы игроков, получивших очко на этом тике
+	Scored []int
+}
+
+// World — игровое поле без привязки к Ebiten: при одинаковых уровне, правилах,
+// зерне и вводе результат всегда одинаковый. Змей может быть несколько —
+// по одной на игрока, индекс змеи совпадает с индексом игрока.
+type World struct {
+	Level  *core.Level
+	Snakes []*core.Snake
+	Food   *core.Food
+
+	Scores []int
+	Moves  []int
+	Causes []DeathCause
+	Tick   int
+	// DeathCause — причина окончания игры: первая смерть, истечение времени или остановка
+	DeathCause DeathCause
+
+	mode    GameMode
+	rules   Rules
+	players int
+	seed    uint64
+	rng     *rand.Rand
+	walls   map[core.Position]bool
+	// nextFixedFood — индекс следующей клетки из Level.Food.Fixed
+	nextFixedFood int
+}
+
+func NewWorld(level *core.Level, rules Rules, mode GameMode, players int, seed uint64) (*World, error) {
+	if level == nil {
+		return nil, fmt.Errorf("invalid level: nil")
+	}
+	if level.GridWidth <= 0 || level.GridHeight <= 0 {
+		return nil, fmt.Errorf("invalid level size: %dx%d", level.GridWidth, level.GridHeight)
+	}
+	if players < 1 {
+		return nil, fmt.Errorf("invalid number of players: expected positive value, received %d", players)
+	}
+	if mode == nil {
+		mode = ClassicMode{}
+	}
+	for i, spawn := range level.Spawns {
+		if spawn.X < 0 || spawn.X >= level.GridWidth || spawn.Y < 0 || spawn.Y >= level.GridHeight {
+			return nil, fmt.Errorf("invalid spawn %d: position (%d, %d) is outside the level", i, spawn.X, spawn.Y)
+		}
+	}
+
+	w := &World{
+		Level:   level,
+		mode:    mode,
+		rules:   rules,
+		players: players,
+		walls:   make(map[core.Position]bool, len(level.Walls)),
+	}
+	for _, wall := range level.Walls {
+		w.walls[wall.Position] = true
+	}
+
+	if err := w.Reset(seed); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Reset начинает игру заново с указанным зерном.
+func (w *World) Reset(seed uint64) error {
+	length := w.rules.InitialSnakeLen
+	if w.Level.InitialLength > 0 {
+		length = w.Level.InitialLength
+	}
+	interval := w.rules.InitialSpeed
+	if curveInterval, ok := w.Level.MoveInterval(0); ok {
+		interval = curveInterval
+	}
+
+	// длина из настроек не знает уровня: укорачиваем змей до места за самым тесным стартом,
+	// чтобы хвост не оказался за краем поля или в стене, и все игроки начинали на равных
+	for i := range w.players {
+		pos, direction := w.spawnPoint(i)
+		// короче MinSnakeLength не укорачиваем: такой старт отвергнет checkSpawns
+		length = min(length, max(w.roomBehind(pos, direction, length), core.MinSnakeLength))
+	}
+
+	w.Snakes = make([]*core.Snake, w.players)
+	for i := range w.Snakes {
+		pos, direction := w.spawnPoint(i)
+		snake, err := core.NewSnake(pos.X, pos.Y, direction, length, interval, w.rules.MaxSpeed)
+		if err != nil {
+			return fmt.Errorf("не удалось создать змею: %w", err)
+		}
+		snake.SetTurnQueueSize(w.rules.TurnQueue)
+		w.Snakes[i] = snake
+	}
+	if err := w.checkSpawns(); err != nil {
+		return err
+	}
+
+	w.Food = nil
+	w.Scores = make([]int, w.players)
+	w.Moves = make([]int, w.players)
+	w.Causes = make([]DeathCause, w.players)
+	w.Tick = 0
+	w.DeathCause = NotDead
+	w.seed = seed
+	w.rng = rand.New(rand.NewPCG(seed, seed))
+	w.nextFixedFood = 0
+
+	if w.mode.SpawnsFood() {
+		w.spawnFood()
+	}
+	return nil
+}
+
+// roomBehind считает, сколько свободных клеток подряд (не больше limit) занимает змея
+// с головой в head, смотрящая в direction.
+func (w *World) roomBehind(head core.Position, direction core.Direction, limit int) int {
+	dx, dy := direction.Delta()
+	for i := range limit {
+		pos := core.Position{X: head.X - i*dx, Y: head.Y - i*dy}
+		if !w.IsInside(pos) || w.walls[pos] {
+			return i
+		}
+	}
+	return limit
+}
+
+// checkSpawns проверяет, что змеи на старте целиком на поле, не стоят на стенах и не налезают
+// друг на друга: старты по умолчанию в уровне не записаны, и проверка уровня могла их не видеть.
+func (w *World) checkSpawns() error {
+	owners := make(map[core.Position]int)
+	for i, snake := range w.Snakes {
+		for _, segment := range snake.Body {
+			pos := segment.Position
+			if !w.IsInside(pos) {
+				return fmt.Errorf("invalid spawn %d: snake cell (%d, %d) is out of bounds", i, pos.X, pos.Y)
+			}
+			if w.walls[pos] {
+				return fmt.Errorf("invalid spawn %d: snake cell (%d, %d) is on a wall", i, pos.X, pos.Y)
+			}
+			if other, taken := owners[pos]; taken {
+				return fmt.Errorf("invalid spawn %d: snake overlaps snake %d at (%d, %d)", i, other, pos.X, pos.Y)
+			}
+			owners[pos] = i
+		}
+	}
+	return nil
+}
+
+func (w *World) spawnPoint(player int) (core.Position, core.Direction) {
+	return SpawnPoint(w.Level, player, w.players)
+}
+
+// SpawnPoint берёт старт игрока из уровня, а если его там нет — один игрок стартует
+// в центре поля, двое — на разных строках навстречу друг другу.
+func SpawnPoint(level *core.Level, player, players int) (core.Position, core.Direction) {
+	if player < len(level.Spawns) {
+		spawn := level.Spawns[player]
+		return spawn.Position, spawn.Direction
+	}
+
+	width, height := level.GridWidth, level.GridHeight
+	if players == 1 {
+		return core.Position{X: width / 2, Y: height / 2}, core.Right
+	}
+	row := height * (player + 1) / (players + 1)
+	if player%2 == 0 {
+		return core.Position{X: width / 2, Y: row}, core.Right
+	}
+	return core.Position{X: width/2 - 1, Y: row}, core.Left
+}
+
+func (w *World) Seed() uint64 {
+	return w.seed
+}
+
+func (w *World) Rules() Rules {
+	return w.rules
+}
+
+func (w *World) Mode() GameMode {
+	return w.mode
+}
+
+func (w *World) Players() int {
+	return w.players
+}
+
+func (w *World) IsOver() bool {
+	return w.DeathCause != NotDead
+}
+
+func (w *World) IsWall(pos core.Position) bool {
+	return w.walls[pos]
+}
+
+func (w *World) IsInside(pos core.Position) bool {
+	return pos.X >= 0 && pos.X < w.Level.GridWidth && pos.Y >= 0 && pos.Y < w.Level.GridHeight
+}
+
+// Winner возвращает индекс победителя: единственного выжившего, а если выжили все
+// или никто — игрока с наибольшим счётом. При равенстве возвращает -1.
+func (w *World) Winner() int {
+	if w.players == 1 {
+		return 0
+	}
+
+	alive := make([]int, 0, w.players)
+	for i, snake := range w.Snakes {
+		if snake.IsAlive {
+			alive = append(alive, i)
+		}
+	}
+	if len(alive) == 1 {
+		return alive[0]
+	}
+	if len(alive) == 0 {
+		for i := range w.Snakes {
+			alive = append(alive, i)
+		}
+	}
+
+	winner, bestScore, isTie := -1, -1, false
+	for _, i := range alive {
+		switch {
+		case w.Scores[i] > bestScore:
+			winner, bestScore, isTie = i, w.Scores[i], false
+		case w.Scores[i] == bestScore:
+			isTie = true
+		}
+	}
+	if isTie {
+		return -1
+	}
+	return winner
+}
+
+// Step продвигает игру на один тик; inputs[i] — ввод игрока i.
+func (w *World) Step(inputs ...Input) Events {
+	var events Events
+	if w.IsOver() {
+		return events
+	}
+	w.Tick++
+
+	for _, input := range inputs {
+		if input.Finish {
+			w.DeathCause = Stopped
+			return events
+		}
+	}
+
+	for i, input := range inputs {
+		if i >= len(w.Snakes) {
+			break
+		}
+		if input.Replace {
+			w.Snakes[i].ClearTurns()
+		}
+		for _, direction := range input.Turns {
+			w.Snakes[i].SetNextDirection(direction)
+		}
+	}
+
+	moved := make([]bool, len(w.Snakes))
+	for i, snake := range w.Snakes {
+		if !snake.IsAlive || !snake.Update() {
+			continue
+		}
+		moved[i] = true
+		events.Moved = true
+		w.Moves[i]++
+		if w.mode.WrapsBorders() {
+			snake.Body[0].Position = w.wrap(snake.Body[0].Position)
+		}
+	}
+
+	for i := range w.Snakes {
+		if moved[i] {
+			w.feed(i, &events)
+		}
+	}
+	for i := range w.Snakes {
+		if moved[i] {
+			w.checkCollisions(i)
+		}
+	}
+
+	for _, cause := range w.Causes {
+		if cause != NotDead {
+			events.Died = true
+			if w.DeathCause == NotDead {
+				w.DeathCause = cause
+			}
+		}
+	}
+
+	if !w.IsOver() && w.mode.IsFinished(w) {
+		w.DeathCause = TimeUp
+	}
+	return events
+}
+
+func (w *World) feed(player int, events *Events) {
+	snake := w.Snakes[player]
+	head := snake.Body[0].Position
+
+	switch {
+	case w.mode.SpawnsFood() && w.Food != nil && w.Food.Position == head:
+		events.AteFood = true
+		w.addPoint(player, events)
+		w.spawnFood()
+	case w.mode.Grows(w, player):
+		w.addPoint(player, events)
+	default:
+		// длина змеи всегда больше 1, поэтому ошибки здесь быть не может
+		_ = snake.CutTail()
+	}
+}
+
+func (w *World) checkCollisions(player int) {
+	snake := w.Snakes[player]
+	head := snake.Body[0].Position
+
+	if w.walls[head] {
+		w.collide(player, WallCollision)
+	}
+	if !w.IsInside(head) {
+		w.collide(player, BorderCollision)
+	}
+	if snake.HitsItself() {
+		w.collide(player, SelfCollision)
+	}
+
+	// столкновение головой с любой клеткой другой змеи; если обе змеи шагнули
+	// в одну клетку, погибнут обе — каждая увидит здесь голову соперника
+	for other, otherSnake := range w.Snakes {
+		if other == player {
+			continue
+		}
+		for _, segment := range otherSnake.Body {
+			if segment.Position == head {
+				w.collide(player, SnakeCollision)
+				break
+			}
+		}
+	}
+}
+
+func (w *World) addPoint(player int, events *Events) {
+	w.Scores[player]++
+	events.Scored = append(events.Scored, player)
+	if len(w.Level.SpeedCurve) > 0 {
+		previous, _ := w.Level.MoveInterval(w.Scores[player] - 1)
+		if interval, ok := w.Level.MoveInterval(w.Scores[player]); ok && interval != previous {
+			w.Snakes[player].SetMoveInterval(interval)
+			events.SpeedUp = true
+		}
+		return
+	}
+	if w.rules.SpeedIncreaseInterval > 0 && w.Scores[player]%w.rules.SpeedIncreaseInterval == 0 {
+		w.Snakes[player].DecreaseMoveInterval(w.rules.SpeedIncreaseAmount)
+		events.SpeedUp = true
+	}
+}
+
+func (w *World) wrap(pos core.Position) core.Position {
+	width, height := w.Level.GridWidth, w.Level.GridHeight
+	return core.Position{
+		X: (pos.X%width + width) % width,
+		Y: (pos.Y%height + height) % height,
+	}
+}
+
+func (w *World) collide(player int, cause DeathCause) {
+	if w.Causes[player] != NotDead || !w.mode.IsLethal(cause) {
+		return
+	}
+	w.Snakes[player].IsAlive = false
+	w.Causes[player] = cause
+}
+
+func (w *World) spawnFood() {
+	occupiedCells := make(map[core.Position]bool)
+	for pos := range w.walls {
+		occupiedCells[pos] = true
+	}
+	for _, snake := range w.Snakes {
+		for _, snakePart := range snake.Body {
+			occupiedCells[snakePart.Position] = true
+		}
+	}
+
+	freeCells := make([]core.Position, 0)
+	for i := 0; i < w.Level.GridWidth; i++ {
+		for j := 0; j < w.Level.GridHeight; j++ {
+			if !occupiedCells[core.Position{X: i, Y: j}] {
+				freeCells = append(freeCells, core.Position{X: i, Y: j})
+			}
+		}
+	}
+
+	if len(freeCells) == 0 {
+		w.Food = nil
+		return
+	}
+
+	cell, ok := w.levelFoodCell(occupiedCells, freeCells)
+	if !ok {
+		cell = freeCells[w.rng.IntN(len(freeCells))]
+	}
+	w.Food = core.NewFood(cell.X, cell.Y)
+}
+
+// levelFoodCell выбирает клетку для еды по правилам уровня: сначала фиксированные
+// клетки по очереди, затем взвешенные зоны. false — правил нет или все их клетки заняты.
+func (w *World) levelFoodCell(occupiedCells map[core.Position]bool, freeCells []core.Position) (core.Position, bool) {
+	rules := w.Level.Food
+	if rules == nil {
+		return core.Position{}, false
+	}
+
+	for range rules.Fixed {
+		cell := rules.Fixed[w.nextFixedFood%len(rules.Fixed)]
+		w.nextFixedFood++
+		if w.IsInside(cell) && !occupiedCells[cell] {
+			return cell, true
+		}
+	}
+
+	zoneCells := make([][]core.Position, len(rules.Zones))
+	totalWeight := 0
+	for i, zone := range rules.Zones {
+		for _, cell := range freeCells {
+			if zone.Contains(cell) {
+				zoneCells[i] = append(zoneCells[i], cell)
+			}
+		}
+		if len(zoneCells[i]) > 0 {
+			totalWeight += zone.EffectiveWeight()
+		}
+	}
+	if totalWeight == 0 {
+		return core.Position{}, false
+	}
+
+	choice := w.rng.IntN(totalWeight)
+	for i, zone := range rules.Zones {
+		if len(zoneCells[i]) == 0 {
+			continue
+		}
+		if choice < zone.EffectiveWeight() {
+			return zoneCells[i][w.rng.IntN(len(zoneCells[i]))], true
+		}
+		choice -= zone.EffectiveWeight()
+	}
+	return core.Position{}, false
+}
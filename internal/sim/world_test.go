@@ -0,0 +1,421 @@
+package sim
+
+import (
+	"snake-game/internal/core"
+	"testing"
+)
+
+// testRules — змея длины 3 шагает каждый тик, ускорения нет.
+func testRules() Rules {
+	return Rules{
+		InitialSnakeLen: 3,
+		InitialSpeed:    1,
+		MaxSpeed:        1,
+		TurnQueue:       TurnQueueSize,
+	}
+}
+
+// newTestLevel — поле 10x8 с едой в дальнем углу, чтобы она не попадалась змеям на пути.
+// Без spawns первая змея стартует в (2, 2) вправо.
+func newTestLevel(walls []core.Position, spawns ...core.Spawn) *core.Level {
+	level := core.NewLevel("test", 10, 8, nil)
+	for _, pos := range walls {
+		level.Walls = append(level.Walls, core.Wall{Position: pos})
+	}
+	if len(spawns) == 0 {
+		spawns = []core.Spawn{{Position: core.Position{X: 2, Y: 2}, Direction: core.Right}}
+	}
+	level.Spawns = spawns
+	level.Food = &core.FoodRules{Fixed: []core.Position{{X: 9, Y: 7}}}
+	return level
+}
+
+func newTestWorld(t *testing.T, level *core.Level, rules Rules, mode GameMode) *World {
+	t.Helper()
+
+	w, err := NewWorld(level, rules, mode, max(len(level.Spawns), 1), 1)
+	if err != nil {
+		t.Fatalf("NewWorld: %v", err)
+	}
+	return w
+}
+
+// steer возвращает ввод всех игроков, где первый игрок поворачивает в direction.
+func steer(players int, direction core.Direction, ok bool) []Input {
+	inputs := make([]Input, players)
+	if ok {
+		inputs[0].Turns = []core.Direction{direction}
+	}
+	return inputs
+}
+
+func TestWorldCollisions(t *testing.T) {
+	tests := []struct {
+		name   string
+		walls  []core.Position
+		spawns []core.Spawn
+		length int
+		// turns[i] — поворот первого игрока перед шагом i+1
+		turns      map[int]core.Direction
+		wantTick   int
+		wantCauses []DeathCause
+	}{
+		{
+			name:       "wall ahead",
+			walls:      []core.Position{{X: 5, Y: 2}},
+			wantTick:   3,
+			wantCauses: []DeathCause{WallCollision},
+		},
+		{
+			name:       "border",
+			wantTick:   8,
+			wantCauses: []DeathCause{BorderCollision},
+		},
+		{
+			name:       "own body",
+			spawns:     []core.Spawn{{Position: core.Position{X: 5, Y: 2}, Direction: core.Right}},
+			length:     5,
+			turns:      map[int]core.Direction{0: core.Down, 1: core.Left, 2: core.Up},
+			wantTick:   3,
+			wantCauses: []DeathCause{SelfCollision},
+		},
+		{
+			name: "body of another snake",
+			spawns: []core.Spawn{
+				{Position: core.Position{X: 2, Y: 2}, Direction: core.Right},
+				{Position: core.Position{X: 6, Y: 5}, Direction: core.Up},
+			},
+			wantTick:   4,
+			wantCauses: []DeathCause{SnakeCollision, NotDead},
+		},
+		{
+			name: "head-on kills both",
+			spawns: []core.Spawn{
+				{Position: core.Position{X: 2, Y: 2}, Direction: core.Right},
+				{Position: core.Position{X: 7, Y: 2}, Direction: core.Left},
+			},
+			wantTick:   3,
+			wantCauses: []DeathCause{SnakeCollision, SnakeCollision},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := testRules()
+			if tt.length > 0 {
+				rules.InitialSnakeLen = tt.length
+			}
+			w := newTestWorld(t, newTestLevel(tt.walls, tt.spawns...), rules, ClassicMode{})
+
+			for i := 0; i < 2*tt.wantTick && !w.IsOver(); i++ {
+				direction, ok := tt.turns[i]
+				w.Step(steer(w.Players(), direction, ok)...)
+			}
+
+			if w.Tick != tt.wantTick {
+				t.Fatalf("game ended on tick %d, want %d", w.Tick, tt.wantTick)
+			}
+			for i, want := range tt.wantCauses {
+				if w.Causes[i] != want {
+					t.Fatalf("player %d died of %s, want %s", i, w.Causes[i], want)
+				}
+			}
+			if w.DeathCause != tt.wantCauses[0] {
+				t.Fatalf("DeathCause = %s, want %s", w.DeathCause, tt.wantCauses[0])
+			}
+		})
+	}
+}
+
+func TestWorldEatingGrowsSnake(t *testing.T) {
+	level := newTestLevel(nil)
+	level.Food = &core.FoodRules{Fixed: []core.Position{{X: 4, Y: 2}, {X: 6, Y: 2}, {X: 9, Y: 7}}}
+	w := newTestWorld(t, level, testRules(), ClassicMode{})
+
+	tests := []struct {
+		tick      int
+		wantAte   bool
+		wantScore int
+		wantLen   int
+		wantFood  core.Position
+	}{
+		{tick: 1, wantScore: 0, wantLen: 3, wantFood: core.Position{X: 4, Y: 2}},
+		{tick: 2, wantAte: true, wantScore: 1, wantLen: 4, wantFood: core.Position{X: 6, Y: 2}},
+		{tick: 3, wantScore: 1, wantLen: 4, wantFood: core.Position{X: 6, Y: 2}},
+		{tick: 4, wantAte: true, wantScore: 2, wantLen: 5, wantFood: core.Position{X: 9, Y: 7}},
+		{tick: 5, wantScore: 2, wantLen: 5, wantFood: core.Position{X: 9, Y: 7}},
+	}
+	for _, tt := range tests {
+		events := w.Step(Input{})
+		if w.Tick != tt.tick {
+			t.Fatalf("tick = %d, want %d", w.Tick, tt.tick)
+		}
+		if events.AteFood != tt.wantAte {
+			t.Fatalf("tick %d: AteFood = %v, want %v", tt.tick, events.AteFood, tt.wantAte)
+		}
+		if tt.wantAte && (len(events.Scored) != 1 || events.Scored[0] != 0) {
+			t.Fatalf("tick %d: Scored = %v, want [0]", tt.tick, events.Scored)
+		}
+		if w.Scores[0] != tt.wantScore {
+			t.Fatalf("tick %d: score = %d, want %d", tt.tick, w.Scores[0], tt.wantScore)
+		}
+		if got := len(w.Snakes[0].Body); got != tt.wantLen {
+			t.Fatalf("tick %d: snake length = %d, want %d", tt.tick, got, tt.wantLen)
+		}
+		if w.Food == nil || w.Food.Position != tt.wantFood {
+			t.Fatalf("tick %d: food = %+v, want %v", tt.tick, w.Food, tt.wantFood)
+		}
+	}
+}
+
+func TestWorldSpeedUp(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules Rules
+		curve []core.SpeedPoint
+		// wantMoves — тики, на которых змея шагнула; wantSpeedUps — тики с ускорением
+		wantMoves    []int
+		wantSpeedUps []int
+	}{
+		{
+			name:         "every two points down to max speed",
+			rules:        Rules{InitialSnakeLen: 3, InitialSpeed: 4, SpeedIncreaseInterval: 2, SpeedIncreaseAmount: 1, MaxSpeed: 2},
+			wantMoves:    []int{4, 8, 11, 14, 16, 18},
+			wantSpeedUps: []int{8, 14, 18},
+		},
+		{
+			name:         "level speed curve replaces rules",
+			rules:        Rules{InitialSnakeLen: 3, InitialSpeed: 9, SpeedIncreaseInterval: 1, SpeedIncreaseAmount: 1, MaxSpeed: 1},
+			curve:        []core.SpeedPoint{{Score: 0, Interval: 4}, {Score: 2, Interval: 2}},
+			wantMoves:    []int{4, 8, 10, 12, 14, 16},
+			wantSpeedUps: []int{8},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level := newTestLevel(nil)
+			level.GridWidth = 30
+			level.SpeedCurve = tt.curve
+			// в выживании очко даётся за каждый шаг, так счёт растёт без еды
+			w := newTestWorld(t, level, tt.rules, SurvivalMode{GrowEvery: 1})
+
+			moves, speedUps := make([]int, 0), make([]int, 0)
+			for len(moves) < len(tt.wantMoves) && w.Tick < 100 {
+				events := w.Step(Input{})
+				if events.Moved {
+					moves = append(moves, w.Tick)
+				}
+				if events.SpeedUp {
+					speedUps = append(speedUps, w.Tick)
+				}
+			}
+
+			if !equalInts(moves, tt.wantMoves) {
+				t.Fatalf("moves on ticks %v, want %v", moves, tt.wantMoves)
+			}
+			if !equalInts(speedUps, tt.wantSpeedUps) {
+				t.Fatalf("speed-ups on ticks %v, want %v", speedUps, tt.wantSpeedUps)
+			}
+		})
+	}
+}
+
+func TestWorldModes(t *testing.T) {
+	tests := []struct {
+		mode  GameMode
+		walls []core.Position
+		ticks int
+		// finish — после ticks тиков игрок сам завершает игру
+		finish    bool
+		wantCause DeathCause
+		wantTick  int
+		wantScore int
+		wantLen   int
+	}{
+		{mode: ClassicMode{}, ticks: 20, wantCause: BorderCollision, wantTick: 8, wantLen: 3},
+		{mode: WrapMode{}, ticks: 20, wantCause: NotDead, wantTick: 20, wantLen: 3},
+		{mode: WrapMode{}, walls: []core.Position{{X: 5, Y: 2}}, ticks: 20, wantCause: WallCollision, wantTick: 3, wantLen: 3},
+		{mode: TimeAttackMode{Limit: 5}, ticks: 20, wantCause: TimeUp, wantTick: 5, wantLen: 3},
+		{mode: SurvivalMode{GrowEvery: 2}, ticks: 4, wantCause: NotDead, wantTick: 4, wantScore: 2, wantLen: 5},
+		{mode: ZenMode{}, walls: []core.Position{{X: 5, Y: 2}}, ticks: 20, finish: true, wantCause: Stopped, wantTick: 21, wantLen: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode.Name(), func(t *testing.T) {
+			w := newTestWorld(t, newTestLevel(tt.walls), testRules(), tt.mode)
+			if hasFood := w.Food != nil; hasFood != tt.mode.SpawnsFood() {
+				t.Fatalf("food on the field: %v, want %v", hasFood, tt.mode.SpawnsFood())
+			}
+
+			for w.Tick < tt.ticks && !w.IsOver() {
+				w.Step(Input{})
+			}
+			if tt.finish {
+				w.Step(Input{Finish: true})
+			}
+
+			if w.DeathCause != tt.wantCause {
+				t.Fatalf("DeathCause = %s, want %s", w.DeathCause, tt.wantCause)
+			}
+			if w.Tick != tt.wantTick {
+				t.Fatalf("tick = %d, want %d", w.Tick, tt.wantTick)
+			}
+			if w.Scores[0] != tt.wantScore {
+				t.Fatalf("score = %d, want %d", w.Scores[0], tt.wantScore)
+			}
+			if got := len(w.Snakes[0].Body); got != tt.wantLen {
+				t.Fatalf("snake length = %d, want %d", got, tt.wantLen)
+			}
+		})
+	}
+}
+
+// TestWorldReplaceDropsQueuedTurns — бот сначала решил повернуть вверх, а до шага передумал
+// и поехал прямо: без Replace змея всё равно повернула бы вверх.
+func TestWorldReplaceDropsQueuedTurns(t *testing.T) {
+	tests := []struct {
+		name     string
+		second   Input
+		wantHead core.Position
+	}{
+		{
+			name:     "queued turn runs",
+			second:   Input{Turns: []core.Direction{core.Right}},
+			wantHead: core.Position{X: 2, Y: 1},
+		},
+		{
+			name:     "replace forgets it",
+			second:   Input{Turns: []core.Direction{core.Right}, Replace: true},
+			wantHead: core.Position{X: 3, Y: 2},
+		},
+		{
+			name:     "replace with a new turn",
+			second:   Input{Turns: []core.Direction{core.Down}, Replace: true},
+			wantHead: core.Position{X: 2, Y: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := testRules()
+			rules.InitialSpeed = 3
+			w := newTestWorld(t, newTestLevel(nil), rules, ClassicMode{})
+
+			w.Step(Input{Turns: []core.Direction{core.Up}})
+			w.Step(tt.second)
+			for w.Moves[0] == 0 {
+				w.Step(Input{})
+			}
+
+			if head := w.Snakes[0].Body[0].Position; head != tt.wantHead {
+				t.Fatalf("head at %v after the first move, want %v", head, tt.wantHead)
+			}
+		})
+	}
+}
+
+func TestNewWorldSpawns(t *testing.T) {
+	wallAt := func(x, y int) []core.Wall { return []core.Wall{{Position: core.Position{X: x, Y: y}}} }
+
+	tests := []struct {
+		name    string
+		level   *core.Level
+		players int
+		length  int
+		wantErr bool
+		wantLen int
+	}{
+		{
+			name:    "default spawn of one player",
+			level:   core.NewLevel("open", 20, 10, wallAt(10, 3)),
+			players: 1,
+			length:  3,
+			wantLen: 3,
+		},
+		{
+			name:    "default spawn of the first of two players on a wall",
+			level:   core.NewLevel("open", 20, 10, wallAt(10, 3)),
+			players: 2,
+			length:  3,
+			wantErr: true,
+		},
+		{
+			name:    "default spawn of the second of two players on a wall",
+			level:   core.NewLevel("open", 20, 10, wallAt(9, 6)),
+			players: 2,
+			length:  3,
+			wantErr: true,
+		},
+		{
+			name: "level spawn on a wall",
+			level: &core.Level{Name: "walled", GridWidth: 10, GridHeight: 8, Walls: wallAt(3, 2),
+				Spawns: []core.Spawn{{Position: core.Position{X: 3, Y: 2}, Direction: core.Right}}},
+			players: 1,
+			length:  3,
+			wantErr: true,
+		},
+		{
+			name: "overlapping spawns",
+			level: &core.Level{Name: "crowded", GridWidth: 10, GridHeight: 8, Spawns: []core.Spawn{
+				{Position: core.Position{X: 3, Y: 2}, Direction: core.Right},
+				{Position: core.Position{X: 2, Y: 1}, Direction: core.Up},
+			}},
+			players: 2,
+			length:  3,
+			wantErr: true,
+		},
+		{
+			name: "long snake is cut to the room behind the spawn",
+			level: &core.Level{Name: "edge", GridWidth: 10, GridHeight: 8,
+				Spawns: []core.Spawn{{Position: core.Position{X: 3, Y: 2}, Direction: core.Right}}},
+			players: 1,
+			length:  12,
+			wantLen: 4,
+		},
+		{
+			name: "wall behind the spawn cuts every snake",
+			level: &core.Level{Name: "edge", GridWidth: 10, GridHeight: 8, Walls: wallAt(1, 5), Spawns: []core.Spawn{
+				{Position: core.Position{X: 6, Y: 2}, Direction: core.Right},
+				{Position: core.Position{X: 3, Y: 5}, Direction: core.Right},
+			}},
+			players: 2,
+			length:  5,
+			wantLen: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := testRules()
+			rules.InitialSnakeLen = tt.length
+			w, err := NewWorld(tt.level, rules, ClassicMode{}, tt.players, 1)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("NewWorld succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewWorld: %v", err)
+			}
+			for i, snake := range w.Snakes {
+				if len(snake.Body) != tt.wantLen {
+					t.Fatalf("snake %d has length %d, want %d", i, len(snake.Body), tt.wantLen)
+				}
+			}
+		})
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
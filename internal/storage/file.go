@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// fileRecord — представление записи в JSON-файле, поля совпадают с колонками таблицы records.
+type fileRecord struct {
+	ID            int64     `json:"id"`
+	PlayerName    string    `json:"player_name"`
+	Score         int       `json:"score"`
+	TimeInSeconds int       `json:"time_in_seconds"`
+	LevelName     string    `json:"level_name"`
+	CreatedAt     time.Time `json:"created_at"`
+	ReplayFile    string    `json:"replay_file,omitempty"`
+	Mode          string    `json:"mode,omitempty"`
+}
+
+type FileRepository struct {
+	path   string
+	memory *MemoryRepository
+	logger *slog.Logger
+}
+
+func NewFileRepository(path string, log *slog.Logger) (Repository, error) {
+	repo := &FileRepository{
+		path:   path,
+		memory: NewMemoryRepository(log),
+		logger: log,
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create records directory: %w", err)
+	}
+
+	if err := repo.load(); err != nil {
+		return nil, err
+	}
+
+	log.Info("file storage opened", "path", path, "records", len(repo.memory.records))
+	return repo, nil
+}
+
+func (r *FileRepository) load() error {
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read records file: %w", err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	var stored []fileRecord
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return fmt.Errorf("failed to parse records file %s: %w", r.path, err)
+	}
+
+	for _, fr := range stored {
+		record := NewRecord(fr.PlayerName, fr.Score, time.Duration(fr.TimeInSeconds)*time.Second, fr.LevelName, fr.CreatedAt)
+		record.ReplayFile = fr.ReplayFile
+		record.Mode = fr.Mode
+		r.memory.add(*record)
+	}
+	return nil
+}
+
+func (r *FileRepository) SaveRecord(ctx context.Context, record *Record) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.memory.mu.Lock()
+	defer r.memory.mu.Unlock()
+
+	r.memory.add(*record)
+	if err := r.flush(); err != nil {
+		r.memory.records = r.memory.records[:len(r.memory.records)-1]
+		r.logger.Error("failed to save record", "error", err)
+		return err
+	}
+
+	r.logger.Info("record saved successfully", "player", record.PlayerName, "score", record.Score, "time_in_sec", record.Time, "level", record.LevelName)
+	return nil
+}
+
+// flush перезаписывает файл целиком через временный файл, чтобы не оставить его повреждённым.
+func (r *FileRepository) flush() error {
+	stored := make([]fileRecord, 0, len(r.memory.records))
+	for _, record := range r.memory.records {
+		stored = append(stored, fileRecord{
+			ID:            record.ID,
+			PlayerName:    record.PlayerName,
+			Score:         record.Score,
+			TimeInSeconds: int(record.Time.Seconds()),
+			LevelName:     record.LevelName,
+			CreatedAt:     record.CreatedAt,
+			ReplayFile:    record.ReplayFile,
+			Mode:          record.Mode,
+		})
+	}
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal records: %w", err)
+	}
+
+	tmpPath := r.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write records file: %w", err)
+	}
+	if err := os.Rename(tmpPath, r.path); err != nil {
+		return fmt.Errorf("failed to replace records file: %w", err)
+	}
+	return nil
+}
+
+func (r *FileRepository) GetTopRecords(ctx context.Context, filter Filter) ([]Record, error) {
+	return r.memory.GetTopRecords(ctx, filter)
+}
+
+func (r *FileRepository) Close() error {
+	return nil
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type MemoryRepository struct {
+	mu      sync.RWMutex
+	records []Record
+	nextID  int64
+	logger  *slog.Logger
+}
+
+func NewMemoryRepository(log *slog.Logger) *MemoryRepository {
+	return &MemoryRepository{
+		records: make([]Record, 0),
+		nextID:  1,
+		logger:  log,
+	}
+}
+
+func (r *MemoryRepository) SaveRecord(ctx context.Context, record *Record) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.add(*record)
+
+	r.logger.Info("record saved successfully", "player", record.PlayerName, "score", record.Score, "time_in_sec", record.Time, "level", record.LevelName)
+	return nil
+}
+
+// add сохраняет запись так же, как это делает Postgres: время с точностью до секунды,
+// дата создания проставляется при сохранении.
+func (r *MemoryRepository) add(record Record) {
+	record.ID = r.nextID
+	record.Time = record.Time.Truncate(time.Second)
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+	if record.Mode == "" {
+		record.Mode = DefaultMode
+	}
+	r.records = append(r.records, record)
+	r.nextID++
+}
+
+func (r *MemoryRepository) GetTopRecords(ctx context.Context, filter Filter) ([]Record, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return applyFilter(r.records, filter), nil
+}
+
+func (r *MemoryRepository) Close() error {
+	return nil
+}
+
+// applyFilter повторяет семантику запроса из PostgresRepository.GetTopRecords:
+// префикс имени игрока, точные имя уровня и режим, сортировка по очкам и времени, лимит.
+func applyFilter(records []Record, filter Filter) []Record {
+	result := make([]Record, 0)
+	for _, record := range records {
+		if filter.playerNamePrefix != "" && !strings.HasPrefix(record.PlayerName, filter.playerNamePrefix) {
+			continue
+		}
+		if filter.levelName != "" && record.LevelName != filter.levelName {
+			continue
+		}
+		if filter.mode != "" && record.Mode != filter.mode {
+			continue
+		}
+		result = append(result, record)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			if filter.isScoreAsc {
+				return result[i].Score < result[j].Score
+			}
+			return result[i].Score > result[j].Score
+		}
+		if filter.isTimeAsc {
+			return result[i].Time < result[j].Time
+		}
+		return result[i].Time > result[j].Time
+	})
+
+	if filter.playersMaxNumber > 0 && len(result) > filter.playersMaxNumber {
+		result = result[:filter.playersMaxNumber]
+	}
+	return result
+}
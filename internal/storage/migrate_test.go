@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// openTestDB создаёт в Postgres из DATABASE_URL (например, из docker-compose) отдельную схему
+// и возвращает строку подключения к ней. Схема удаляется после теста, данные игры не затрагиваются.
+func openTestDB(t *testing.T) string {
+	t.Helper()
+
+	connStr := os.Getenv("DATABASE_URL")
+	if connStr == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+
+	admin, err := OpenPostgres(connStr)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("migrate_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatalf("failed to create schema %s: %v", schema, err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`); err != nil {
+			t.Errorf("failed to drop schema %s: %v", schema, err)
+		}
+	})
+
+	u, err := url.Parse(connStr)
+	if err != nil {
+		t.Fatalf("failed to parse DATABASE_URL: %v", err)
+	}
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func newTestMigrator(t *testing.T, connStr string) *Migrator {
+	t.Helper()
+
+	db, err := OpenPostgres(connStr)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := NewMigrator(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+	return migrator
+}
+
+func appliedCount(t *testing.T, migrator *Migrator) int {
+	t.Helper()
+
+	statuses, err := migrator.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	count := 0
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			count++
+		}
+	}
+	return count
+}
+
+func TestMigratorUpDownStatus(t *testing.T) {
+	migrator := newTestMigrator(t, openTestDB(t))
+	ctx := context.Background()
+	total := len(migrator.migrations)
+
+	if got := appliedCount(t, migrator); got != 0 {
+		t.Fatalf("fresh schema: %d applied migrations, want 0", got)
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if applied != total {
+		t.Fatalf("Up applied %d migrations, want %d", applied, total)
+	}
+	if applied, err := migrator.Up(ctx); err != nil || applied != 0 {
+		t.Fatalf("second Up = %d, %v; want 0, nil", applied, err)
+	}
+	if got := appliedCount(t, migrator); got != total {
+		t.Fatalf("after Up: %d applied migrations, want %d", got, total)
+	}
+
+	reverted, err := migrator.Down(ctx, 1)
+	if err != nil {
+		t.Fatalf("Down(1): %v", err)
+	}
+	if reverted != 1 {
+		t.Fatalf("Down(1) reverted %d migrations, want 1", reverted)
+	}
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if last := statuses[len(statuses)-1]; last.AppliedAt != nil {
+		t.Fatalf("migration %04d_%s is still applied after Down(1)", last.Version, last.Name)
+	}
+	if version, err := migrator.Version(ctx); err != nil || version != total-1 {
+		t.Fatalf("Version = %d, %v; want %d, nil", version, err, total-1)
+	}
+
+	// шагов больше, чем применённых миграций: откатывается всё, что есть
+	reverted, err = migrator.Down(ctx, total+1)
+	if err != nil {
+		t.Fatalf("Down(%d): %v", total+1, err)
+	}
+	if reverted != total-1 {
+		t.Fatalf("Down(%d) reverted %d migrations, want %d", total+1, reverted, total-1)
+	}
+	if got := appliedCount(t, migrator); got != 0 {
+		t.Fatalf("after full Down: %d applied migrations, want 0", got)
+	}
+
+	if _, err := migrator.Down(ctx, 0); err == nil {
+		t.Fatal("Down(0) must fail")
+	}
+}
+
+func TestMigratorConcurrentUp(t *testing.T) {
+	connStr := openTestDB(t)
+	const clients = 4
+
+	migrators := make([]*Migrator, clients)
+	for i := range migrators {
+		migrators[i] = newTestMigrator(t, connStr)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		applied = make([]int, clients)
+		errs    = make([]error, clients)
+	)
+	for i, migrator := range migrators {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			applied[i], errs[i] = migrator.Up(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	sum := 0
+	for i := range migrators {
+		if errs[i] != nil {
+			t.Fatalf("client %d: Up: %v", i, errs[i])
+		}
+		sum += applied[i]
+	}
+	// каждая миграция применена ровно одним из клиентов
+	if total := len(migrators[0].migrations); sum != total {
+		t.Fatalf("clients applied %d migrations in total, want %d", sum, total)
+	}
+}
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS records(
+    id SERIAL PRIMARY KEY,
+    player_name VARCHAR(50) NOT NULL,
+    score INT NOT NULL,
+    time_in_seconds INT NOT NULL,
+    level_name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1 @@
+ALTER TABLE records ADD COLUMN IF NOT EXISTS replay_file VARCHAR(255) NOT NULL DEFAULT '';
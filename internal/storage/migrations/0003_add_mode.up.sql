@@ -0,0 +1 @@
+ALTER TABLE records ADD COLUMN IF NOT EXISTS mode VARCHAR(20) NOT NULL DEFAULT 'classic';
@@ -17,17 +17,9 @@ type PostgresRepository struct {
 
 func NewPostgresRepository(connStr string, log *slog.Logger) (Repository, error) {
 	log.Debug("im in NewPostgresRepository")
-	db, err := sql.Open("pgx", connStr)
+	db, err := OpenPostgres(connStr)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open database connection: %w", err)
-	}
-
-	log.Debug("2. im in NewPostgresRepository")
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel() // Важно вызывать cancel, чтобы освободить ресурсы контекста
-
-	if err := db.PingContext(ctx); err != nil {
-		return nil, fmt.Errorf("failed to ping database within 5s: %w", err)
+		return nil, err
 	}
 	log.Debug("3. im in NewPostgresRepository")
 
@@ -38,7 +30,8 @@ func NewPostgresRepository(connStr string, log *slog.Logger) (Repository, error)
 
 	log.Debug("4. im in NewPostgresRepository")
 
-	if err := repo.initSchema(); err != nil {
+	if err := repo.migrate(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to initialize schema: %w", err)
 	}
 
@@ -48,26 +41,41 @@ func NewPostgresRepository(connStr string, log *slog.Logger) (Repository, error)
 	return repo, nil
 }
 
-func (r *PostgresRepository) initSchema() error {
-	query := `CREATE TABLE IF NOT EXISTS records(
-    id SERIAL PRIMARY KEY,
-    player_name VARCHAR(50) NOT NULL,
-    score INT NOT NULL,
-    time_in_seconds INT NOT NULL,
-    level_name VARCHAR(50) NOT NULL,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-	)`
-	_, err := r.db.Exec(query)
+// OpenPostgres открывает соединение и проверяет, что база доступна.
+func OpenPostgres(connStr string) (*sql.DB, error) {
+	db, err := sql.Open("pgx", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel() // Важно вызывать cancel, чтобы освободить ресурсы контекста
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database within 5s: %w", err)
+	}
+	return db, nil
+}
+
+func (r *PostgresRepository) migrate() error {
+	migrator, err := NewMigrator(r.db, r.logger)
 	if err != nil {
 		return err
 	}
+
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		return err
+	}
+	r.logger.Info("schema is up to date", "applied_migrations", applied)
 	return nil
 }
 
 func (r *PostgresRepository) SaveRecord(ctx context.Context, record *Record) error {
-	query := `INSERT INTO records (player_name, score, time_in_seconds, level_name) VALUES ($1, $2, $3, $4)`
+	query := `INSERT INTO records (player_name, score, time_in_seconds, level_name, replay_file, mode) VALUES ($1, $2, $3, $4, $5, $6)`
 
-	result, err := r.db.ExecContext(ctx, query, record.PlayerName, record.Score, int(record.Time.Seconds()), record.LevelName)
+	result, err := r.db.ExecContext(ctx, query, record.PlayerName, record.Score, int(record.Time.Seconds()), record.LevelName, record.ReplayFile, record.Mode)
 	if err != nil {
 		r.logger.Error("failed to save record", "error", err)
 		return err
@@ -88,7 +96,7 @@ func (r *PostgresRepository) SaveRecord(ctx context.Context, record *Record) err
 }
 
 func (r *PostgresRepository) GetTopRecords(ctx context.Context, filter Filter) ([]Record, error) {
-	baseQuery := `SELECT player_name, score, time_in_seconds, level_name, created_at FROM records`
+	baseQuery := `SELECT player_name, score, time_in_seconds, level_name, created_at, replay_file, mode FROM records`
 
 	var whereClauses []string
 	var args []interface{}
@@ -106,6 +114,12 @@ func (r *PostgresRepository) GetTopRecords(ctx context.Context, filter Filter) (
 		argID++
 	}
 
+	if filter.mode != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("mode = $%d", argID))
+		args = append(args, filter.mode)
+		argID++
+	}
+
 	query := baseQuery
 	if len(whereClauses) > 0 {
 		query += " WHERE " + strings.Join(whereClauses, " AND ")
@@ -136,9 +150,11 @@ func (r *PostgresRepository) GetTopRecords(ctx context.Context, filter Filter) (
 			time_in_sec int
 			levelName   string
 			created_at  time.Time
+			replayFile  string
+			mode        string
 		)
 
-		err = rows.Scan(&playerName, &score, &time_in_sec, &levelName, &created_at)
+		err = rows.Scan(&playerName, &score, &time_in_sec, &levelName, &created_at, &replayFile, &mode)
 		if err != nil {
 			r.logger.Error("failed to scan row", "err", err)
 			return nil, err
@@ -149,6 +165,8 @@ func (r *PostgresRepository) GetTopRecords(ctx context.Context, filter Filter) (
 		}
 
 		record := NewRecord(playerName, score, time.Duration(time_in_sec)*time.Second, levelName, created_at)
+		record.ReplayFile = replayFile
+		record.Mode = mode
 		records = append(records, *record)
 	}
 
@@ -2,6 +2,9 @@ package storage
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"snake-game/internal/config"
 	"time"
 )
 
@@ -12,6 +15,8 @@ type Record struct {
 	Time       time.Duration
 	LevelName  string
 	CreatedAt  time.Time
+	ReplayFile string
+	Mode       string
 }
 
 func NewRecord(playerName string, score int, time time.Duration, levelName string, created_at time.Time) *Record {
@@ -24,21 +29,27 @@ func NewRecord(playerName string, score int, time time.Duration, levelName strin
 		Time:       time,
 		LevelName:  levelName,
 		CreatedAt:  created_at,
+		Mode:       DefaultMode,
 	}
 }
 
+// DefaultMode — режим игры для записей, сохранённых до появления режимов.
+const DefaultMode = "classic"
+
 type Filter struct {
 	playerNamePrefix string
 	levelName        string
+	mode             string
 	isScoreAsc       bool
 	isTimeAsc        bool
 	playersMaxNumber int
 }
 
-func NewFilter(playerNamePrefix, levelName string, isScoreAsc, isTimeAsc bool, playersMaxNumber int) *Filter {
+func NewFilter(playerNamePrefix, levelName, mode string, isScoreAsc, isTimeAsc bool, playersMaxNumber int) *Filter {
 	return &Filter{
 		playerNamePrefix: playerNamePrefix,
 		levelName:        levelName,
+		mode:             mode,
 		isScoreAsc:       isScoreAsc,
 		isTimeAsc:        isTimeAsc,
 		playersMaxNumber: playersMaxNumber,
@@ -50,3 +61,20 @@ type Repository interface {
 	GetTopRecords(ctx context.Context, filter Filter) ([]Record, error)
 	Close() error
 }
+
+// Open создаёт хранилище рекордов, выбранное в конфигурации.
+func Open(cfg *config.Config, log *slog.Logger) (Repository, error) {
+	switch cfg.Storage {
+	case config.StoragePostgres:
+		if cfg.DatabaseURL == "" {
+			return nil, fmt.Errorf("postgres storage selected but DATABASE_URL is not set")
+		}
+		return NewPostgresRepository(cfg.DatabaseURL, log)
+	case config.StorageFile:
+		return NewFileRepository(cfg.RecordsFile, log)
+	case config.StorageMemory:
+		return NewMemoryRepository(log), nil
+	default:
+		return nil, fmt.Errorf("unknown storage type %q", cfg.Storage)
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"snake-game/internal/assets"
 )
 
+// ClickSound, если задан, вызывается при нажатии любой кнопки: так игра озвучивает меню.
+var ClickSound func()
+
 type Button struct {
 	X, Y, Width, Height float64
 	Text                string
@@ -39,6 +42,9 @@ func (button *Button) Update() {
 		float64(cursorY) >= button.Y && float64(cursorY) < button.Y+button.Height {
 		button.IsHovered = true
 		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+			if ClickSound != nil {
+				ClickSound()
+			}
 			if button.OnClick != nil {
 				button.OnClick()
 			}
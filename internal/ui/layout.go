@@ -0,0 +1,74 @@
+package ui
+
+const (
+	// MinWidth и MinHeight — наименьший логический экран, под который разложены сцены.
+	// Окно любого размера показывает его целиком: интерфейс масштабируется под окно,
+	// а лишнее место по одной из сторон достаётся сценам
+	MinWidth  = 1920
+	MinHeight = 1200
+)
+
+// Screen — логический экран, по которому сцены раскладывают интерфейс: кнопки привязываются
+// к его центру и краям, а поле уровня вписывается в него целиком.
+type Screen struct {
+	Width, Height int
+	TopBarHeight  int
+}
+
+// NewScreen подбирает логический экран для окна outsideWidth×outsideHeight: с теми же
+// пропорциями, что и окно, и не меньше MinWidth×MinHeight.
+func NewScreen(outsideWidth, outsideHeight, topBarHeight int) Screen {
+	outsideWidth, outsideHeight = max(outsideWidth, 1), max(outsideHeight, 1)
+	scale := min(float64(outsideWidth)/MinWidth, float64(outsideHeight)/MinHeight)
+	return Screen{
+		Width:        max(int(float64(outsideWidth)/scale), MinWidth),
+		Height:       max(int(float64(outsideHeight)/scale), MinHeight),
+		TopBarHeight: topBarHeight,
+	}
+}
+
+// Field вписывает поле gridWidth×gridHeight в экран под верхней панелью: клетка наибольшего
+// целого размера, при котором поле помещается, а само поле по центру.
+func (s Screen) Field(gridWidth, gridHeight int) Field {
+	gridWidth, gridHeight = max(gridWidth, 1), max(gridHeight, 1)
+	areaHeight := s.Height - s.TopBarHeight
+	tileSize := max(min(s.Width/gridWidth, areaHeight/gridHeight), 1)
+	return Field{
+		// углы поля — на целых пикселях, иначе клетки размываются
+		X:          float64((s.Width - gridWidth*tileSize) / 2),
+		Y:          float64(s.TopBarHeight + (areaHeight-gridHeight*tileSize)/2),
+		TileSize:   float64(tileSize),
+		GridWidth:  gridWidth,
+		GridHeight: gridHeight,
+	}
+}
+
+// Field — место поля уровня на экране: левый верхний угол и размер клетки.
+type Field struct {
+	X, Y     float64
+	TileSize float64
+
+	GridWidth, GridHeight int
+}
+
+func (f Field) Width() float64 {
+	return float64(f.GridWidth) * f.TileSize
+}
+
+func (f Field) Height() float64 {
+	return float64(f.GridHeight) * f.TileSize
+}
+
+// Cell возвращает левый верхний угол клетки (x, y) на экране.
+func (f Field) Cell(x, y int) (float64, float64) {
+	return f.X + float64(x)*f.TileSize, f.Y + float64(y)*f.TileSize
+}
+
+// CellAt возвращает клетку под точкой экрана (px, py); false — точка вне поля.
+func (f Field) CellAt(px, py int) (x, y int, ok bool) {
+	fx, fy := float64(px)-f.X, float64(py)-f.Y
+	if fx < 0 || fy < 0 || fx >= f.Width() || fy >= f.Height() {
+		return 0, 0, false
+	}
+	return int(fx / f.TileSize), int(fy / f.TileSize), true
+}